@@ -0,0 +1,195 @@
+package discordrus
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// ATTACHMENT_FIELD_KEY is the key used to pass file attachments in logrus fields
+	// The value can be an Attachment, *Attachment or []Attachment
+	ATTACHMENT_FIELD_KEY = "attachments"
+
+	// DefaultMaxAttachmentSize is the default cap for a single streamed attachment
+	DefaultMaxAttachmentSize int64 = 8 << 20 // 8 MB
+)
+
+// Attachment is a file sent together with the log entry
+//
+// The content is streamed directly into the webhook request, so large generated files
+// don't have to be buffered by the caller. At most the configured maximum attachment size
+// is copied; longer content is cut and ends with a truncation note.
+//
+// A request body may have to be rebuilt (e.g. when Discord redirects the request),
+// so the content should be re-readable:
+//   - Open is called every time the body is built; the returned reader is closed after use
+//     if it implements io.Closer. This is the preferred form for files and generators.
+//   - Reader is rewound when it implements io.Seeker (bytes.Reader, strings.Reader, *os.File).
+//     Any other reader can only be read once; rebuilding the body then fails instead of
+//     sending an empty file.
+//
+// If Reader implements io.Closer it is closed once the entry has been delivered.
+// Note that with asynchronous delivery the content is read after Fire has returned.
+type Attachment struct {
+	Name   string                    // File name shown in Discord, e.g. "report.csv"
+	Reader io.Reader                 // Attachment content
+	Open   func() (io.Reader, error) // Optional, reopens the content (takes precedence over Reader)
+}
+
+// errAttachmentConsumed is returned when a one-shot attachment reader has to be read again
+var errAttachmentConsumed = eris.New("attachment reader was already consumed and cannot be rewound; use Attachment.Open")
+
+// WithMaxAttachmentSize sets the maximum number of bytes copied from a single attachment reader
+func WithMaxAttachmentSize(n int64) Option {
+	return func(h *Hook) {
+		h.maxAttachmentSize = n
+	}
+}
+
+// attachmentLimit returns the configured attachment cap or the default one
+func (h *Hook) attachmentLimit() int64 {
+	if h.maxAttachmentSize > 0 {
+		return h.maxAttachmentSize
+	}
+	return DefaultMaxAttachmentSize
+}
+
+// entryAttachments returns the attachments passed in entry.Data[ATTACHMENT_FIELD_KEY]
+func entryAttachments(entry *logrus.Entry) []attachment {
+	var list []Attachment
+	switch v := entry.Data[ATTACHMENT_FIELD_KEY].(type) {
+	case Attachment:
+		list = []Attachment{v}
+	case *Attachment:
+		if v != nil {
+			list = []Attachment{*v}
+		}
+	case []Attachment:
+		list = v
+	}
+
+	var files []attachment
+	for _, a := range list {
+		if a.Reader == nil && a.Open == nil {
+			continue
+		}
+		name := a.Name
+		if name == "" {
+			name = fmt.Sprintf("attachment-%d.txt", len(files)+1)
+		}
+		files = append(files, attachment{Name: name, source: newAttachmentSource(a)})
+	}
+	return files
+}
+
+// attachmentSource produces the content of a streamed attachment, once per body build
+type attachmentSource struct {
+	open    func() (io.Reader, error)
+	reader  io.Reader
+	offset  int64
+	seeker  bool
+	started bool
+	closed  bool
+}
+
+func newAttachmentSource(a Attachment) *attachmentSource {
+	src := &attachmentSource{open: a.Open, reader: a.Reader}
+	if a.Open == nil {
+		if s, ok := a.Reader.(io.Seeker); ok {
+			if off, err := s.Seek(0, io.SeekCurrent); err == nil {
+				src.offset = off
+				src.seeker = true
+			}
+		}
+	}
+	return src
+}
+
+// get returns a reader positioned at the start of the content and a function releasing it
+func (s *attachmentSource) get() (io.Reader, func(), error) {
+	if s.open != nil {
+		r, err := s.open()
+		if err != nil {
+			return nil, nil, err
+		}
+		return r, func() {
+			if c, ok := r.(io.Closer); ok {
+				c.Close()
+			}
+		}, nil
+	}
+
+	if s.started {
+		if !s.seeker {
+			return nil, nil, errAttachmentConsumed
+		}
+		if _, err := s.reader.(io.Seeker).Seek(s.offset, io.SeekStart); err != nil {
+			return nil, nil, err
+		}
+	}
+	s.started = true
+	return s.reader, func() {}, nil
+}
+
+// close releases the caller provided reader once the message is done
+func (s *attachmentSource) close() {
+	if s.closed {
+		return
+	}
+	s.closed = true
+	if c, ok := s.reader.(io.Closer); ok {
+		c.Close()
+	}
+}
+
+// copyCapped copies at most limit bytes from src to dst. When src holds more data,
+// a truncation note is written instead of the remainder.
+func copyCapped(dst io.Writer, src io.Reader, limit int64) error {
+	_, err := io.Copy(dst, &cappedReader{r: src, limit: limit, remaining: limit})
+	return err
+}
+
+// cappedReader reads at most limit bytes from r, followed by a truncation note
+// when r holds more data. Close calls release, if set.
+type cappedReader struct {
+	r         io.Reader
+	limit     int64
+	remaining int64
+	note      io.Reader
+	release   func()
+}
+
+func (c *cappedReader) Read(p []byte) (int, error) {
+	if c.note != nil {
+		return c.note.Read(p)
+	}
+	if c.remaining <= 0 {
+		// Cek apakah masih ada sisa data setelah batas
+		var probe [1]byte
+		if n, _ := io.ReadFull(c.r, probe[:]); n > 0 {
+			c.note = strings.NewReader(fmt.Sprintf("\n… [truncated: attachment exceeds %d bytes]", c.limit))
+			return c.note.Read(p)
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > c.remaining {
+		p = p[:c.remaining]
+	}
+	n, err := c.r.Read(p)
+	c.remaining -= int64(n)
+	if err == io.EOF && c.remaining <= 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (c *cappedReader) Close() error {
+	if c.release != nil {
+		c.release()
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package discordrus
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithBackfillThreshold tags entries older than threshold as backfilled.
+// When replaying historical logs (entry.Time far in the past), the alert title gets a
+// "(BACKFILLED)" suffix and the original time is shown prominently, so it doesn't look
+// like the incident just happened.
+func WithBackfillThreshold(threshold time.Duration) Option {
+	return func(h *Hook) {
+		h.backfillAfter = threshold
+	}
+}
+
+// isBackfilled reports whether the entry is older than the backfill threshold
+func (h *Hook) isBackfilled(entry *logrus.Entry) bool {
+	if h.backfillAfter <= 0 || entry.Time.IsZero() {
+		return false
+	}
+	return time.Since(entry.Time) > h.backfillAfter
+}
+
+// markBackfilled tags the level embed and adds the original time as a field
+func markBackfilled(embed *Embed, originalTime time.Time) {
+	embed.Title += " (BACKFILLED)"
+	embed.Fields = append(embed.Fields, originallyLogged(originalTime))
+}
+
+// originallyLogged returns a field showing when the entry was logged
+// Discord renders <t:unix:F> in the reader's timezone and <t:unix:R> as relative time
+func originallyLogged(t time.Time) EmbedField {
+	unix := t.Unix()
+	return EmbedField{
+		Name:  "Originally Logged",
+		Value: fmt.Sprintf("<t:%d:F> (<t:%d:R>)", unix, unix),
+	}
+}
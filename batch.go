@@ -0,0 +1,203 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/murbagus/discordrus/sender"
+	"github.com/rotisserie/eris"
+)
+
+const (
+	// maxEmbedsPerMessage is Discord's limit of embeds in a single webhook message
+	maxEmbedsPerMessage = 10
+
+	// maxFilesPerMessage is Discord's limit of attachments in a single webhook message
+	maxFilesPerMessage = sender.MaxFiles
+
+	// maxEmbedCharsPerMessage is Discord's limit of characters across all embeds of a message
+	maxEmbedCharsPerMessage = 6000
+)
+
+// WithBatching coalesces bursts of entries into a single webhook call.
+// Entries for the same webhook fired within window are grouped, up to maxEntries entries
+// per message (and within Discord's limit of 10 embeds per message).
+// Batching only applies to asynchronous delivery.
+func WithBatching(maxEntries int, window time.Duration) Option {
+	return func(h *Hook) {
+		if maxEntries <= 1 || window <= 0 {
+			h.batch = nil
+			return
+		}
+		h.batch = &batcher{
+			h:          h,
+			maxEntries: maxEntries,
+			window:     window,
+			queues:     make(map[string]*batchQueue),
+		}
+	}
+}
+
+// batcher groups messages per destination webhook
+type batcher struct {
+	h          *Hook
+	maxEntries int
+	window     time.Duration
+
+	mu     sync.Mutex
+	queues map[string]*batchQueue
+}
+
+// batchQueue holds the messages waiting for one destination
+type batchQueue struct {
+	msgs   []*message
+	embeds int
+	chars  int
+	files  int
+	timer  *time.Timer
+}
+
+// add queues the message; it is sent once the window expires or the batch is full
+func (b *batcher) add(m *message) {
+	embeds, chars, err := embedStats(m.Payload)
+	if err != nil {
+		b.h.finish([]*message{m}, err)
+		return
+	}
+
+	b.mu.Lock()
+	key := m.URL + "\x00" + m.threadID + "\x00" + m.threadName
+	q, ok := b.queues[key]
+	if !ok {
+		q = &batchQueue{}
+		b.queues[key] = q
+	}
+
+	// Kirim batch yang ada dulu jika pesan ini tidak muat lagi
+	if len(q.msgs) > 0 && (q.embeds+embeds > maxEmbedsPerMessage ||
+		q.chars+chars > maxEmbedCharsPerMessage ||
+		q.files+len(m.Files) > maxFilesPerMessage) {
+		b.flushLocked(q)
+	}
+
+	q.msgs = append(q.msgs, m)
+	q.embeds += embeds
+	q.chars += chars
+	q.files += len(m.Files)
+
+	if len(q.msgs) >= b.maxEntries {
+		b.flushLocked(q)
+	} else if q.timer == nil {
+		var t *time.Timer
+		t = time.AfterFunc(b.window, func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			// Timer lama yang kalah cepat dengan flush lain diabaikan
+			if q.timer == t {
+				b.flushLocked(q)
+			}
+		})
+		q.timer = t
+	}
+	b.mu.Unlock()
+}
+
+// flushAll sends every queued batch immediately
+func (b *batcher) flushAll() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, q := range b.queues {
+		b.flushLocked(q)
+	}
+}
+
+// flushLocked takes the queued messages and delivers them in the background
+// b.mu must be held
+func (b *batcher) flushLocked(q *batchQueue) {
+	if q.timer != nil {
+		q.timer.Stop()
+	}
+	msgs := q.msgs
+	*q = batchQueue{}
+	if len(msgs) == 0 {
+		return
+	}
+
+	go func() {
+		// Pesan yang sudah basi dibuang sebelum digabung
+		var fresh []*message
+		for _, m := range msgs {
+			if !b.h.dropStale(m) {
+				fresh = append(fresh, m)
+			}
+		}
+		var err error
+		if len(fresh) > 0 {
+			var merged *message
+			merged, err = mergeMessages(fresh)
+			if err == nil {
+				err = b.h.deliver(merged)
+			}
+		}
+		b.h.finish(msgs, err)
+	}()
+}
+
+// finish reports the delivery result of queued messages and releases them
+func (h *Hook) finish(msgs []*message, err error) {
+	for _, m := range msgs {
+		if err != nil {
+			h.reportError(err, m.entry)
+		} else {
+			h.muteDelivered(m)
+		}
+		m.close()
+		h.pending.done()
+	}
+}
+
+// mergeMessages combines messages for the same webhook into one message:
+// the embeds and files of all messages are concatenated in order
+func mergeMessages(msgs []*message) (*message, error) {
+	if len(msgs) == 1 {
+		return msgs[0], nil
+	}
+
+	var base map[string]any
+	var embeds []any
+	merged := &message{URL: msgs[0].URL, threadID: msgs[0].threadID, threadName: msgs[0].threadName}
+	for i, m := range msgs {
+		var p map[string]any
+		if err := json.Unmarshal(m.Payload, &p); err != nil {
+			return nil, eris.Wrap(err, "failed to merge batched payloads")
+		}
+		if i == 0 {
+			base = p
+		}
+		if e, ok := p["embeds"].([]any); ok {
+			embeds = append(embeds, e...)
+		}
+		merged.Files = append(merged.Files, m.Files...)
+		merged.batched = append(merged.batched, m.entries()...)
+	}
+	base["embeds"] = embeds
+
+	payload, err := json.Marshal(base)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to merge batched payloads")
+	}
+	merged.Payload = payload
+	return merged, nil
+}
+
+// embedStats returns the number of embeds in the payload and the characters
+// they count against Discord's per-message limit
+func embedStats(payload []byte) (int, int, error) {
+	var p WebhookPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return 0, 0, eris.Wrap(err, "invalid webhook payload")
+	}
+	return len(p.Embeds), embedChars(p.Embeds), nil
+}
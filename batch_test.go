@@ -0,0 +1,103 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// embedCounts returns a Discord webhook stub recording the number of embeds of every message
+func embedCounts(t *testing.T) (*httptest.Server, func() []int) {
+	t.Helper()
+	var mu sync.Mutex
+	var counts []int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p WebhookPayload
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &p); err != nil {
+			t.Errorf("invalid payload: %v", err)
+		}
+		mu.Lock()
+		counts = append(counts, len(p.Embeds))
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, func() []int {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]int(nil), counts...)
+	}
+}
+
+// singleEmbed formats every entry as one embed, so batches are easy to count
+var singleEmbed = FormatterFunc(func(entry *logrus.Entry) (*WebhookPayload, error) {
+	return &WebhookPayload{Embeds: []Embed{{Title: entry.Message}}}, nil
+})
+
+// waitFor polls until cond holds or the timeout passes
+func waitFor(timeout time.Duration, cond func() bool) bool {
+	deadline := time.Now().Add(timeout)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return true
+}
+
+func TestBatchFlushesAtSize(t *testing.T) {
+	srv, counts := embedCounts(t)
+	h := NewHook(srv.URL, WithFormatter(singleEmbed), WithBatching(3, time.Hour))
+	defer h.Close()
+
+	logger := logrus.New()
+	for i := 0; i < 6; i++ {
+		if err := h.Fire(errorEntry(logger, "batched")); err != nil {
+			t.Fatalf("Fire: %v", err)
+		}
+	}
+	// Window satu jam, jadi batch hanya terkirim karena sudah penuh
+	if !waitFor(5*time.Second, func() bool { return len(counts()) == 2 }) {
+		t.Fatalf("got messages %v, want 2 full batches", counts())
+	}
+	for _, n := range counts() {
+		if n != 3 {
+			t.Errorf("batch has %d embeds, want 3", n)
+		}
+	}
+}
+
+func TestBatchFlushesAtInterval(t *testing.T) {
+	srv, counts := embedCounts(t)
+	h := NewHook(srv.URL, WithFormatter(singleEmbed), WithBatching(10, 50*time.Millisecond))
+	defer h.Close()
+
+	logger := logrus.New()
+	for i := 0; i < 2; i++ {
+		if err := h.Fire(errorEntry(logger, "batched")); err != nil {
+			t.Fatalf("Fire: %v", err)
+		}
+	}
+	if !waitFor(5*time.Second, func() bool { return len(counts()) == 1 }) {
+		t.Fatalf("got messages %v, want the batch after the window", counts())
+	}
+	if got := counts(); got[0] != 2 {
+		t.Errorf("batch has %d embeds, want 2", got[0])
+	}
+
+	// Entry berikutnya memulai window baru
+	if err := h.Fire(errorEntry(logger, "later")); err != nil {
+		t.Fatalf("Fire: %v", err)
+	}
+	if !waitFor(5*time.Second, func() bool { return len(counts()) == 2 }) {
+		t.Fatalf("got messages %v, want a second batch", counts())
+	}
+}
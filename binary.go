@@ -0,0 +1,51 @@
+package discordrus
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+// binaryPreviewSize is the number of leading bytes of a binary body shown as hex
+const binaryPreviewSize = 64
+
+// isBinary reports whether body is not printable text, e.g. protobuf or an image
+func isBinary(body []byte) bool {
+	if !utf8.Valid(body) {
+		return true
+	}
+	for _, b := range body {
+		if b < 0x20 && b != '\t' && b != '\n' && b != '\r' {
+			return true
+		}
+	}
+	return false
+}
+
+// binaryBodyField renders a binary body as the field name, with its size, content type and
+// a hex dump of the first 64 bytes. Longer bodies are returned as an attachment named after file.
+func binaryBodyField(body []byte, contentType, file, name string) (EmbedField, *Attachment) {
+	if mediaType, _, err := mime.ParseMediaType(contentType); err == nil {
+		contentType = mediaType
+	} else {
+		contentType = http.DetectContentType(body)
+	}
+
+	preview := body
+	if len(preview) > binaryPreviewSize {
+		preview = preview[:binaryPreviewSize]
+	}
+	value := fmt.Sprintf("%s, %s\n%s", formatBytes(len(body)), contentType, strings.TrimRight(hex.Dump(preview), "\n"))
+
+	var attachment *Attachment
+	if len(body) > binaryPreviewSize {
+		fileName := file + ".bin"
+		value += "\nfull body attached as " + fileName
+		attachment = &Attachment{Name: fileName, Reader: bytes.NewReader(body)}
+	}
+	return EmbedField{Name: name, Value: "```" + value + " ```"}, attachment
+}
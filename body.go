@@ -0,0 +1,180 @@
+package discordrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"strings"
+)
+
+const (
+	// defaultMaxBodySize is the number of body characters shown in the embed by default,
+	// leaving room for the code block and the truncation notice within a field value
+	defaultMaxBodySize = 900
+
+	// requestBodyFile and responseBodyFile are the base names bodies are attached as
+	// when they do not fit the embed
+	requestBodyFile  = "request-body"
+	responseBodyFile = "response-body"
+)
+
+// WithMaxBodySize sets the number of characters of a request or response body shown in
+// the embed (default 900, at most 1000 to fit Discord's field limit). JSON bodies are
+// indented before rendering. Longer bodies are shown up to the limit and attached in full
+// as request-body.json or response-body.json (.txt for other content).
+func WithMaxBodySize(n int) Option {
+	return func(h *Hook) {
+		h.maxBodySize = n
+	}
+}
+
+// bodyLimit returns the effective number of body characters shown in the embed
+func (h *Hook) bodyLimit() int {
+	switch {
+	case h.maxBodySize <= 0:
+		return defaultMaxBodySize
+	case h.maxBodySize > maxFieldValueLength:
+		return maxFieldValueLength
+	default:
+		return h.maxBodySize
+	}
+}
+
+// bodyField renders a body as the "Body" field, indenting JSON and XML and highlighting
+// JSON, XML and YAML, and cuts it to the body limit. When it was cut, the full rendering is
+// returned as an attachment named after file. Binary bodies are rendered by binaryBodyField.
+func (h *Hook) bodyField(body []byte, contentType, file string) (EmbedField, *Attachment) {
+	if isBinary(body) {
+		return binaryBodyField(body, contentType, file, h.labelSet().BodyBinary)
+	}
+	body = bytes.TrimSpace(body)
+
+	text, syntax := string(body), ""
+	switch {
+	case json.Valid(body):
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, body, "", "  "); err == nil {
+			text, syntax = indented.String(), "json"
+		}
+	case isXMLContentType(contentType):
+		if indented, err := indentXML(body); err == nil {
+			text, syntax = indented, "xml"
+		}
+	case isYAMLContentType(contentType):
+		// YAML sudah ter-indent, cukup diberi syntax highlighting
+		syntax = "yaml"
+	}
+	name := file + ".txt"
+	if syntax != "" {
+		name = file + "." + syntax
+	}
+
+	runes := []rune(text)
+	limit := h.bodyLimit()
+	if len(runes) <= limit {
+		return EmbedField{Name: h.labelSet().Body, Value: codeBlock(syntax, text)}, nil
+	}
+
+	// Potong di akhir baris agar body yang ter-indent tetap mudah dibaca
+	shown := string(runes[:limit])
+	if i := strings.LastIndexByte(shown, '\n'); i > len(shown)/2 {
+		shown = shown[:i]
+	}
+	notice := fmt.Sprintf("… %s truncated, see %s", formatBytes(len(text)-len(shown)), name)
+	field := EmbedField{Name: h.labelSet().Body, Value: codeBlock(syntax, shown+"\n"+notice)}
+	return field, textAttachment(name, text)
+}
+
+// textAttachment returns text as a file attachment, e.g. content too long for an embed
+func textAttachment(name, text string) *Attachment {
+	return &Attachment{Name: name, Reader: strings.NewReader(text)}
+}
+
+// codeBlock wraps text in a code block, highlighted as syntax when set
+func codeBlock(syntax, text string) string {
+	if syntax == "" {
+		return "```" + text + " ```"
+	}
+	return "```" + syntax + "\n" + text + "\n```"
+}
+
+// isXMLContentType reports whether the content type is application/xml, text/xml or +xml
+func isXMLContentType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml")
+}
+
+// isYAMLContentType reports whether the content type is one of the YAML media types
+func isYAMLContentType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml":
+		return true
+	}
+	return strings.HasSuffix(mediaType, "+yaml")
+}
+
+// indentXML re-encodes an XML document with two-space indentation
+// Namespace prefixes are kept as written.
+func indentXML(body []byte) (string, error) {
+	var out strings.Builder
+	dec := xml.NewDecoder(bytes.NewReader(body))
+	enc := xml.NewEncoder(&out)
+	enc.Indent("", "  ")
+	for {
+		token, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			t.Name = prefixedName(t.Name)
+			attrs := make([]xml.Attr, len(t.Attr))
+			for i, a := range t.Attr {
+				attrs[i] = xml.Attr{Name: prefixedName(a.Name), Value: a.Value}
+			}
+			t.Attr = attrs
+			token = t
+		case xml.EndElement:
+			t.Name = prefixedName(t.Name)
+			token = t
+		case xml.ProcInst:
+			// Deklarasi <?xml ... ?> diletakkan di baris sendiri
+			if err := enc.EncodeToken(t); err != nil {
+				return "", err
+			}
+			if err := enc.Flush(); err != nil {
+				return "", err
+			}
+			out.WriteByte('\n')
+			continue
+		case xml.CharData:
+			// Whitespace di antara elemen diganti oleh indentasi encoder
+			if len(bytes.TrimSpace(t)) == 0 {
+				continue
+			}
+		}
+		if err := enc.EncodeToken(xml.CopyToken(token)); err != nil {
+			return "", err
+		}
+	}
+	if err := enc.Flush(); err != nil {
+		return "", err
+	}
+	return out.String(), nil
+}
+
+// prefixedName folds the namespace prefix of a raw XML name into its local part,
+// so the encoder writes it unchanged instead of declaring a namespace
+func prefixedName(n xml.Name) xml.Name {
+	if n.Space == "" {
+		return n
+	}
+	return xml.Name{Local: n.Space + ":" + n.Local}
+}
@@ -0,0 +1,46 @@
+package discordrus
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithCallerTrimPrefixes removes the given path prefixes (e.g. the module path or GOPATH)
+// from the file shown in the "Caller" field, e.g.
+//
+//	discordrus.WithCallerTrimPrefixes("/home/app/src/", "github.com/acme/billing/")
+func WithCallerTrimPrefixes(prefixes ...string) Option {
+	return func(h *Hook) {
+		h.callerTrimPrefixes = append(h.callerTrimPrefixes, prefixes...)
+	}
+}
+
+// callerField renders the caller reported by logrus (logger.SetReportCaller(true))
+func (h *Hook) callerField(entry *logrus.Entry) (EmbedField, bool) {
+	if !entry.HasCaller() {
+		return EmbedField{}, false
+	}
+
+	file := entry.Caller.File
+	for _, prefix := range h.callerTrimPrefixes {
+		if trimmed, ok := strings.CutPrefix(file, prefix); ok {
+			file = trimmed
+			break
+		}
+	}
+	function := entry.Caller.Function
+	for _, prefix := range h.callerTrimPrefixes {
+		if trimmed, ok := strings.CutPrefix(function, prefix); ok {
+			function = trimmed
+			break
+		}
+	}
+
+	value := fmt.Sprintf("%s:%d\n%s", file, entry.Caller.Line, function)
+	return EmbedField{
+		Name:  h.labelSet().Caller,
+		Value: "```" + previewText(value, maxFieldValueLength) + " ```",
+	}, true
+}
@@ -0,0 +1,61 @@
+package discordrus
+
+import "github.com/sirupsen/logrus"
+
+// CaptureMode controls how much of the request payload is captured when an entry fires
+type CaptureMode int
+
+const (
+	// CaptureFull copies the complete request including its body
+	CaptureFull CaptureMode = iota
+	// CaptureMetadata copies method, URL and headers but skips the body,
+	// avoiding the cost of reading and buffering request bodies
+	CaptureMetadata
+	// CaptureNone ignores the request payload entirely
+	CaptureNone
+)
+
+// String returns the name of the capture mode
+func (m CaptureMode) String() string {
+	switch m {
+	case CaptureFull:
+		return "full"
+	case CaptureMetadata:
+		return "metadata"
+	case CaptureNone:
+		return "none"
+	}
+	return "unknown"
+}
+
+// DefaultCaptureModes are the capture modes used when none is configured for a level:
+// Debug and Trace only capture metadata, all other levels capture the full request
+var DefaultCaptureModes = map[logrus.Level]CaptureMode{
+	logrus.DebugLevel: CaptureMetadata,
+	logrus.TraceLevel: CaptureMetadata,
+}
+
+// WithCaptureMode sets the request capture mode for the given levels, e.g.
+//
+//	discordrus.WithCaptureMode(discordrus.CaptureMetadata, logrus.InfoLevel, logrus.DebugLevel)
+func WithCaptureMode(mode CaptureMode, levels ...logrus.Level) Option {
+	return func(h *Hook) {
+		if h.captureModes == nil {
+			h.captureModes = make(map[logrus.Level]CaptureMode, len(levels))
+		}
+		for _, l := range levels {
+			h.captureModes[l] = mode
+		}
+	}
+}
+
+// captureModeFor returns the capture mode of the level
+func (h *Hook) captureModeFor(level logrus.Level) CaptureMode {
+	if mode, ok := h.captureModes[level]; ok {
+		return mode
+	}
+	if mode, ok := DefaultCaptureModes[level]; ok {
+		return mode
+	}
+	return CaptureFull
+}
@@ -0,0 +1,33 @@
+package discordrus
+
+// WithClientInfo adds the client of logged HTTP requests to the request embed: its address
+// (resolved from X-Forwarded-For or Forwarded behind proxies of WithTrustedProxies), the
+// User-Agent and the Referer, so server-side failures can be traced to the client
+// that triggered them
+func WithClientInfo() Option {
+	return func(h *Hook) {
+		h.clientInfo = true
+	}
+}
+
+// clientFields renders the client of the request, see WithClientInfo
+// The address is left out when the payload already has a ClientIP, e.g. from ginhook.
+func (h *Hook) clientFields(p *LoggerHttpRequestPayload) []EmbedField {
+	if !h.clientInfo || p.Request == nil {
+		return nil
+	}
+	labels := h.labelSet()
+	var fields []EmbedField
+	if p.ClientIP == "" && p.Request.RemoteAddr != "" {
+		fields = append(fields, EmbedField{Name: labels.ClientIP, Value: "```" + h.clientIP(p.Request) + " ```", Inline: true})
+	}
+	for _, header := range []struct{ name, field string }{
+		{"User-Agent", labels.UserAgent},
+		{"Referer", labels.Referer},
+	} {
+		if v := p.Request.Header.Get(header.name); v != "" {
+			fields = append(fields, EmbedField{Name: header.field, Value: "```" + previewText(v, maxFieldValueLength) + " ```"})
+		}
+	}
+	return fields
+}
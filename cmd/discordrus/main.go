@@ -0,0 +1,68 @@
+// Command discordrus provides helper tooling for the discordrus hook.
+//
+// Usage:
+//
+//	discordrus replay -dir ./discord-export -webhook https://discord.com/api/webhooks/...
+//
+// The webhook URL can also be passed via the DISCORDRUS_WEBHOOK_URL environment variable.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/murbagus/discordrus"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	switch os.Args[1] {
+	case "replay":
+		os.Exit(replay(os.Args[2:]))
+	case "help", "-h", "--help":
+		usage()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: discordrus <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	fmt.Fprintln(os.Stderr, "  replay    post exported bundles to a Discord webhook")
+}
+
+func replay(args []string) int {
+	fs := flag.NewFlagSet("replay", flag.ExitOnError)
+	dir := fs.String("dir", "", "directory containing exported bundles")
+	webhook := fs.String("webhook", os.Getenv("DISCORDRUS_WEBHOOK_URL"), "Discord webhook URL")
+	secret := fs.String("signing-secret", os.Getenv("DISCORDRUS_SIGNING_SECRET"), "optional HMAC signing secret")
+	_ = fs.Parse(args)
+
+	if *dir == "" || *webhook == "" {
+		fmt.Fprintln(os.Stderr, "replay: -dir and -webhook are required")
+		fs.Usage()
+		return 2
+	}
+
+	var opts []discordrus.Option
+	if *secret != "" {
+		opts = append(opts, discordrus.WithSigningSecret(*secret))
+	}
+
+	sent, err := discordrus.Replay(*dir, *webhook, opts...)
+	fmt.Printf("replayed %d bundle(s)\n", sent)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "replay:", err)
+		return 1
+	}
+	return 0
+}
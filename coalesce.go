@@ -0,0 +1,128 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// WithBurstCoalescing merges identical entries (same fingerprint and destination, see
+// WithDeduplication) fired within window into one message annotated with "×20 in 800ms", e.g. for tight retry
+// loops. The first entry of a burst is held back for window. Coalescing only applies to
+// asynchronous delivery and runs before batching.
+func WithBurstCoalescing(window time.Duration) Option {
+	return func(h *Hook) {
+		if window <= 0 {
+			h.coalesce = nil
+			return
+		}
+		h.coalesce = &coalescer{h: h, window: window, bursts: make(map[string]*burst)}
+	}
+}
+
+// coalescer holds back the first message of each burst of identical messages
+type coalescer struct {
+	h      *Hook
+	window time.Duration
+
+	mu     sync.Mutex
+	bursts map[string]*burst
+}
+
+// burst is a run of identical messages
+type burst struct {
+	first   *message
+	count   int
+	firstAt time.Time
+	lastAt  time.Time
+}
+
+// burstKey identifies identical messages: the destination and the fingerprint of the entry,
+// see Hook.fingerprint
+func (c *coalescer) burstKey(m *message) string {
+	if m.entry != nil {
+		return m.URL + "\x00" + m.threadID + "\x00" + m.threadName + "\x00" + c.h.fingerprint(m.entry)
+	}
+	return m.URL + "\x00" + string(m.Payload)
+}
+
+// add starts a burst with the message or counts it as a repetition of a running burst
+func (c *coalescer) add(m *message) {
+	key := c.burstKey(m)
+	now := time.Now()
+
+	c.mu.Lock()
+	if b, ok := c.bursts[key]; ok {
+		b.count++
+		b.lastAt = now
+		c.mu.Unlock()
+		// Duplikat tidak dikirim, cukup dihitung
+		m.close()
+		c.h.pending.done()
+		return
+	}
+	b := &burst{first: m, count: 1, firstAt: now, lastAt: now}
+	c.bursts[key] = b
+	c.mu.Unlock()
+
+	time.AfterFunc(c.window, func() { c.flush(key, b) })
+}
+
+// flush sends the burst, if it is still running
+func (c *coalescer) flush(key string, b *burst) {
+	c.mu.Lock()
+	if c.bursts[key] != b {
+		c.mu.Unlock()
+		return
+	}
+	delete(c.bursts, key)
+	c.mu.Unlock()
+
+	if b.count > 1 {
+		c.h.annotateTitle(b.first, fmt.Sprintf(" (×%d in %s)", b.count, b.lastAt.Sub(b.firstAt).Round(time.Millisecond)))
+	}
+	c.h.forward(b.first)
+}
+
+// flushAll sends every running burst immediately
+func (c *coalescer) flushAll() {
+	c.mu.Lock()
+	bursts := make(map[string]*burst, len(c.bursts))
+	for key, b := range c.bursts {
+		bursts[key] = b
+	}
+	c.mu.Unlock()
+
+	for key, b := range bursts {
+		c.flush(key, b)
+	}
+}
+
+// annotateTitle appends suffix to the title of the message's first embed
+// The title is shortened first, so the annotated one still fits the embed limits.
+func (h *Hook) annotateTitle(m *message, suffix string) {
+	var p WebhookPayload
+	if err := json.Unmarshal(m.Payload, &p); err != nil || len(p.Embeds) == 0 {
+		return
+	}
+	l := h.limits.withDefaults()
+	title := p.Embeds[0].Title
+	if runeLen(title)+runeLen(suffix) > l.Title {
+		title = l.truncate(title, l.Title-runeLen(suffix))
+	}
+	p.Embeds[0].Title = l.truncate(title+suffix, l.Title)
+	l.fitTotal(&p)
+	if payload, err := json.Marshal(p); err == nil {
+		m.Payload = payload
+	}
+}
+
+// forward hands a queued message on to the batcher, or delivers it
+func (h *Hook) forward(m *message) {
+	if h.batch != nil {
+		h.batch.add(m)
+		return
+	}
+	h.finish([]*message{m}, h.deliver(m))
+}
@@ -0,0 +1,60 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestBurstTitleFitsLimit(t *testing.T) {
+	var mu sync.Mutex
+	var titles []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p WebhookPayload
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &p)
+		mu.Lock()
+		for _, e := range p.Embeds {
+			titles = append(titles, e.Title)
+		}
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	long := strings.Repeat("x", DefaultEmbedLimits.Title)
+	formatter := FormatterFunc(func(entry *logrus.Entry) (*WebhookPayload, error) {
+		return &WebhookPayload{Embeds: []Embed{{Title: long, Description: entry.Message}}}, nil
+	})
+	h := NewHook(srv.URL, WithFormatter(formatter), WithBurstCoalescing(time.Hour))
+
+	logger := logrus.New()
+	for i := 0; i < 3; i++ {
+		if err := h.Fire(errorEntry(logger, "retry failed")); err != nil {
+			t.Fatalf("Fire: %v", err)
+		}
+	}
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(titles) != 1 {
+		t.Fatalf("got %d messages, want the burst as 1 message", len(titles))
+	}
+	if n := utf8.RuneCountInString(titles[0]); n > DefaultEmbedLimits.Title {
+		t.Errorf("title has %d characters, want at most %d", n, DefaultEmbedLimits.Title)
+	}
+	if !strings.Contains(titles[0], "(×3 in ") {
+		t.Errorf("title %q lost the burst annotation", titles[0])
+	}
+}
@@ -0,0 +1,44 @@
+package discordrus
+
+import "github.com/sirupsen/logrus"
+
+// ColorScheme maps log levels to embed colors (decimal RGB, e.g. 0xFF3657)
+type ColorScheme map[logrus.Level]int
+
+// fallbackColor is used for levels missing from the color scheme
+const fallbackColor = 12434877
+
+// DefaultColorScheme is the color scheme used when none is configured
+var DefaultColorScheme = ColorScheme{
+	logrus.PanicLevel: 16725591, // Merah
+	logrus.FatalLevel: 16725591,
+	logrus.ErrorLevel: 16725591,
+	logrus.WarnLevel:  16760630, // Kuning
+	logrus.InfoLevel:  3447003,  // Biru
+	logrus.DebugLevel: 12434877, // Abu-abu
+	logrus.TraceLevel: 9807270,
+}
+
+// WithColorScheme overrides embed colors per level
+// Levels not present in scheme keep their DefaultColorScheme color
+func WithColorScheme(scheme ColorScheme) Option {
+	return func(h *Hook) {
+		if h.colors == nil {
+			h.colors = make(ColorScheme, len(scheme))
+		}
+		for level, color := range scheme {
+			h.colors[level] = color
+		}
+	}
+}
+
+// colorFor returns the embed color of the level
+func (h *Hook) colorFor(level logrus.Level) int {
+	if c, ok := h.colors[level]; ok {
+		return c
+	}
+	if c, ok := DefaultColorScheme[level]; ok {
+		return c
+	}
+	return fallbackColor
+}
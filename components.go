@@ -0,0 +1,245 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/murbagus/discordrus/sender"
+)
+
+// Component types and flags of Discord's component based messages,
+// see https://discord.com/developers/docs/components/reference
+const (
+	componentButton      = 2
+	componentSection     = 9
+	componentTextDisplay = 10
+	componentFile        = 13
+	componentSeparator   = 14
+	componentContainer   = 17
+
+	buttonStyleLink = 5
+
+	// flagComponentsV2 (IS_COMPONENTS_V2) replaces content and embeds by components
+	flagComponentsV2 = 1 << 15
+
+	// Discord menerima paling banyak 40 component dan 4000 karakter teks per pesan
+	maxComponentsPerMessage     = 40
+	maxComponentCharsPerMessage = 4000
+)
+
+// WithComponentsV2 sends messages as Discord components (Components V2) instead of classic
+// embeds: every entry becomes a container in the level color, with the embeds of the
+// entry as sections separated by dividers, single-line fields rendered inline and
+// attachments listed as files. The payload is converted when it is sent, so formatters,
+// batching and deduplication still work on embeds. Component messages are limited to
+// 4000 characters of text, longer payloads are cut.
+func WithComponentsV2() Option {
+	return func(h *Hook) {
+		h.componentsV2 = true
+	}
+}
+
+// component is a Discord message component; the fields used depend on Type
+type component struct {
+	Type        int             `json:"type"`
+	Content     string          `json:"content,omitempty"`      // Text display
+	Components  []component     `json:"components,omitempty"`   // Container, section
+	AccentColor int             `json:"accent_color,omitempty"` // Container
+	Accessory   *component      `json:"accessory,omitempty"`    // Section
+	Style       int             `json:"style,omitempty"`        // Button
+	Label       string          `json:"label,omitempty"`        // Button
+	URL         string          `json:"url,omitempty"`          // Link button
+	File        *componentMedia `json:"file,omitempty"`         // File
+}
+
+// componentMedia references an uploaded file as attachment://name
+type componentMedia struct {
+	URL string `json:"url"`
+}
+
+// componentBudget tracks the components and characters still available in a message
+type componentBudget struct {
+	components int
+	chars      int
+}
+
+// textDisplay returns s as a text display, cut to the characters left
+// It reports false once the message is full.
+func (b *componentBudget) textDisplay(s string) (component, bool) {
+	s = strings.TrimSpace(s)
+	if s == "" || b.components <= 0 || b.chars <= 0 {
+		return component{}, false
+	}
+	runes := []rune(s)
+	if len(runes) > b.chars {
+		// Sisakan tempat untuk elipsis dan penutup code block
+		n := b.chars - 5
+		if n <= 0 {
+			b.chars = 0
+			return component{}, false
+		}
+		s = string(runes[:n]) + "…"
+		if strings.Count(s, "```")%2 == 1 {
+			s += "\n```"
+		}
+	}
+	b.components--
+	b.chars -= len([]rune(s))
+	return component{Type: componentTextDisplay, Content: s}, true
+}
+
+// take reserves n components, reporting false when they do not fit
+func (b *componentBudget) take(n int) bool {
+	if b.components < n {
+		return false
+	}
+	b.components -= n
+	return true
+}
+
+// componentsMessage converts the payload of out to components and asks Discord to
+// render them (?with_components=true). Payloads that cannot be converted are sent as is.
+func (h *Hook) componentsMessage(out *sender.Message) {
+	names := make([]string, len(out.Files))
+	for i, f := range out.Files {
+		names[i] = f.Name
+	}
+	payload, ok := componentsPayload(out.Payload, names)
+	if !ok {
+		return
+	}
+	out.Payload = payload
+	out.Components = true
+}
+
+// componentsPayload rewrites an embed payload to components, see WithComponentsV2; files
+// are the names of the uploaded attachments, which are only shown when a component
+// refers to them. Payloads already carrying components are left unchanged.
+func componentsPayload(data []byte, files []string) ([]byte, bool) {
+	var p WebhookPayload
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, false
+	}
+	if _, ok := p.Extra["components"]; ok {
+		return nil, false
+	}
+
+	budget := &componentBudget{components: maxComponentsPerMessage - len(files), chars: maxComponentCharsPerMessage}
+	var components []component
+	if c, ok := budget.textDisplay(p.Content); ok {
+		components = append(components, c)
+	}
+
+	// Setiap embed dengan timestamp (embed level) memulai container baru
+	current := -1
+	for _, e := range p.Embeds {
+		newContainer := current < 0 || e.Timestamp != "" || e.Color != components[current].AccentColor
+		// Satu component untuk container atau separator
+		if !budget.take(1) {
+			break
+		}
+		rendered := embedComponents(e, budget)
+		if len(rendered) == 0 {
+			budget.components++
+			continue
+		}
+		if newContainer {
+			components = append(components, component{Type: componentContainer, AccentColor: e.Color, Components: rendered})
+			current = len(components) - 1
+			continue
+		}
+		components[current].Components = append(append(components[current].Components, component{Type: componentSeparator}), rendered...)
+	}
+
+	for _, name := range files {
+		components = append(components, component{Type: componentFile, File: &componentMedia{URL: "attachment://" + name}})
+	}
+
+	// Flag yang diberikan lewat Extra tetap dipertahankan
+	flags := 0
+	if raw, ok := p.Extra["flags"]; ok {
+		if b, err := json.Marshal(raw); err == nil {
+			_ = json.Unmarshal(b, &flags)
+		}
+	}
+	if p.Extra == nil {
+		p.Extra = map[string]any{}
+	}
+	p.Extra["flags"] = flags | flagComponentsV2
+	p.Extra["components"] = components
+	p.Content, p.Embeds = "", nil
+
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return nil, false
+	}
+	return payload, true
+}
+
+// embedComponents renders an embed as the text displays of its section: the title
+// and description, the fields and the footer with the timestamp. Embeds with a URL get
+// the title as a section with a link button.
+func embedComponents(e Embed, budget *componentBudget) []component {
+	var out []component
+
+	header := ""
+	if e.Title != "" {
+		header = "### " + e.Title
+	}
+	if e.Description != "" {
+		header += "\n" + e.Description
+	}
+	if e.URL != "" && budget.take(2) {
+		if c, ok := budget.textDisplay(header); ok {
+			button := component{Type: componentButton, Style: buttonStyleLink, Label: "Open", URL: e.URL}
+			out = append(out, component{Type: componentSection, Components: []component{c}, Accessory: &button})
+			budget.chars -= len(button.Label)
+		} else {
+			budget.components += 2
+		}
+	} else if c, ok := budget.textDisplay(header); ok {
+		out = append(out, c)
+	}
+
+	lines := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		if value, ok := inlineCode(f.Value); ok {
+			lines = append(lines, "**"+f.Name+":** "+value)
+		} else {
+			lines = append(lines, "**"+f.Name+"**\n"+f.Value)
+		}
+	}
+	if c, ok := budget.textDisplay(strings.Join(lines, "\n")); ok {
+		out = append(out, c)
+	}
+
+	var footer []string
+	if e.Footer != nil && e.Footer.Text != "" {
+		footer = append(footer, e.Footer.Text)
+	}
+	if t, err := time.Parse(time.RFC3339, e.Timestamp); err == nil {
+		footer = append(footer, "<t:"+strconv.FormatInt(t.Unix(), 10)+":f>")
+	}
+	if len(footer) > 0 {
+		if c, ok := budget.textDisplay("-# " + strings.Join(footer, " · ")); ok {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// inlineCode returns a single-line code block field value as inline code
+func inlineCode(value string) (string, bool) {
+	inner, ok := strings.CutPrefix(value, "```")
+	if !ok {
+		return "", false
+	}
+	inner, ok = strings.CutSuffix(inner, "```")
+	inner = strings.TrimSpace(inner)
+	if !ok || inner == "" || strings.ContainsAny(inner, "\n`") {
+		return "", false
+	}
+	return "`" + inner + "`", true
+}
@@ -0,0 +1,213 @@
+package discordrus
+
+import (
+	"net/url"
+	"strings"
+)
+
+// HookConfig is a read-only, serializable view of a hook's effective configuration
+// Secrets (webhook tokens, signing secret) are never included
+type HookConfig struct {
+	WebhookURL         string            `json:"webhook_url"`
+	Levels             []string          `json:"levels"`
+	Username           string            `json:"username"`
+	AvatarURL          string            `json:"avatar_url,omitempty"`
+	AppName            string            `json:"app_name,omitempty"`
+	AppVersion         string            `json:"app_version,omitempty"`
+	Environment        string            `json:"environment,omitempty"`
+	Hostname           string            `json:"hostname,omitempty"`
+	PayloadVersion     string            `json:"payload_version"`
+	ComponentsV2       bool              `json:"components_v2"`
+	Async              bool              `json:"async"`
+	Workers            int               `json:"workers"`
+	QueueSize          int               `json:"queue_size"`
+	OverflowPolicy     string            `json:"overflow_policy"`
+	SigningEnabled     bool              `json:"signing_enabled"`
+	ClientCertificates int               `json:"client_certificates"`
+	CustomHTTPClient   bool              `json:"custom_http_client"`
+	FallbackWriter     bool              `json:"fallback_writer"`
+	PersistentQueue    string            `json:"persistent_queue,omitempty"`
+	VerifyDelivery     bool              `json:"verify_delivery"`
+	Timeout            string            `json:"timeout,omitempty"`
+	MaxRetries         int               `json:"max_retries"`
+	HealthProbe        string            `json:"health_probe,omitempty"`
+	ExportDir          string            `json:"export_dir,omitempty"`
+	MaxAttachmentSize  int64             `json:"max_attachment_size"`
+	MaxBodySize        int               `json:"max_body_size"`
+	CategoryField      string            `json:"category_field,omitempty"`
+	ThreadID           string            `json:"thread_id,omitempty"`
+	ThreadNames        bool              `json:"thread_names"`
+	AppliedTags        []string          `json:"applied_tags,omitempty"`
+	CategoryRoutes     map[string]string `json:"category_routes,omitempty"`
+	LevelRoutes        map[string]string `json:"level_routes,omitempty"`
+	CaptureModes       map[string]string `json:"capture_modes,omitempty"`
+	MaxAlertAges       map[string]string `json:"max_alert_ages,omitempty"`
+	SampleRates        map[string]int    `json:"sample_rates,omitempty"`
+	FieldOrder         []string          `json:"field_order,omitempty"`
+	RedactedHeaders    []string          `json:"redacted_headers,omitempty"`
+	RedactedBodyKeys   []string          `json:"redacted_body_keys,omitempty"`
+	RedactionPatterns  []string          `json:"redaction_patterns,omitempty"`
+	TrustedProxies     []string          `json:"trusted_proxies,omitempty"`
+	ClientInfo         bool              `json:"client_info"`
+	CurlCommand        bool              `json:"curl_command"`
+	HARAttachment      bool              `json:"har_attachment"`
+	GoroutineDump      bool              `json:"goroutine_dump"`
+	SystemInfo         bool              `json:"system_info"`
+	TraceIDField       string            `json:"trace_id_field,omitempty"`
+	SpanEvents         bool              `json:"span_events"`
+	BatchMaxEntries    int               `json:"batch_max_entries,omitempty"`
+	BatchWindow        string            `json:"batch_window,omitempty"`
+	CoalesceWindow     string            `json:"coalesce_window,omitempty"`
+	DedupWindow        string            `json:"dedup_window,omitempty"`
+	DigestInterval     string            `json:"digest_interval,omitempty"`
+	EmbedLimits        EmbedLimits       `json:"embed_limits"`
+}
+
+// Config returns the effective configuration of the hook with all secrets redacted,
+// e.g. for support tooling or debug endpoints
+func (h *Hook) Config() HookConfig {
+	cfg := HookConfig{
+		WebhookURL:        redactWebhookURL(h.HookUrl),
+		Username:          h.Username,
+		AvatarURL:         h.AvatarURL,
+		AppName:           h.identity.appName,
+		AppVersion:        h.identity.appVersion,
+		Environment:       h.identity.environment,
+		Hostname:          h.identity.hostname,
+		PayloadVersion:    h.payloadVersion.String(),
+		ComponentsV2:      h.componentsV2,
+		Async:             h.Async,
+		Workers:           h.pool.workersOrDefault(),
+		QueueSize:         h.pool.sizeOrDefault(),
+		OverflowPolicy:    h.pool.policy.String(),
+		SigningEnabled:    h.SigningSecret != "",
+		CustomHTTPClient:  h.Client != nil,
+		FallbackWriter:    h.FallbackWriter != nil,
+		VerifyDelivery:    h.verifyDelivery,
+		ClientInfo:        h.clientInfo,
+		CurlCommand:       h.curlCommand,
+		HARAttachment:     h.harAttachment,
+		GoroutineDump:     h.goroutineDump,
+		SystemInfo:        h.systemInfo,
+		TraceIDField:      h.traceIDField,
+		SpanEvents:        h.spanEvents,
+		MaxRetries:        h.retry.MaxRetries,
+		ExportDir:         h.exportDir,
+		MaxAttachmentSize: h.attachmentLimit(),
+		MaxBodySize:       h.bodyLimit(),
+		CategoryField:     h.categoryField,
+		ThreadID:          h.threadID,
+		ThreadNames:       h.threadName != nil,
+		AppliedTags:       append([]string(nil), h.appliedTags...),
+		EmbedLimits:       h.limits.withDefaults(),
+		FieldOrder:        append([]string(nil), h.fieldOrder...),
+		RedactedHeaders:   append([]string(nil), h.redaction.Headers...),
+		RedactedBodyKeys:  append([]string(nil), h.redaction.BodyKeys...),
+	}
+	if cfg.Username == "" {
+		cfg.Username = defaultUsername
+	}
+	if h.timeout > 0 {
+		cfg.Timeout = h.timeout.String()
+	}
+	if h.probe != nil {
+		cfg.HealthProbe = h.probe.interval.String()
+	}
+
+	levels := h.EnabledLevels()
+	cfg.CaptureModes = make(map[string]string, len(levels))
+	for _, l := range levels {
+		cfg.Levels = append(cfg.Levels, l.String())
+		cfg.CaptureModes[l.String()] = h.captureModeFor(l).String()
+		if limit, ok := h.maxAlertAges[l]; ok && limit.age > 0 {
+			if cfg.MaxAlertAges == nil {
+				cfg.MaxAlertAges = make(map[string]string)
+			}
+			cfg.MaxAlertAges[l.String()] = limit.age.String()
+		}
+	}
+
+	if len(h.sampling) > 0 {
+		cfg.SampleRates = make(map[string]int, len(h.sampling))
+		for l, s := range h.sampling {
+			cfg.SampleRates[l.String()] = s.rate
+		}
+	}
+
+	if h.transport != nil && h.transport.TLSClientConfig != nil {
+		cfg.ClientCertificates = len(h.transport.TLSClientConfig.Certificates)
+	}
+
+	for _, p := range h.redaction.Patterns {
+		cfg.RedactionPatterns = append(cfg.RedactionPatterns, p.String())
+	}
+	for _, p := range h.trustedProxies {
+		cfg.TrustedProxies = append(cfg.TrustedProxies, p.String())
+	}
+
+	if h.batch != nil {
+		cfg.BatchMaxEntries = h.batch.maxEntries
+		cfg.BatchWindow = h.batch.window.String()
+	}
+	if h.coalesce != nil {
+		cfg.CoalesceWindow = h.coalesce.window.String()
+	}
+	if h.persist != nil {
+		cfg.PersistentQueue = h.persist.path
+	}
+	if h.dedup != nil {
+		cfg.DedupWindow = h.dedup.window.String()
+	}
+	if h.digest != nil {
+		cfg.DigestInterval = h.digest.interval.String()
+	}
+
+	if len(h.categoryRoutes) > 0 {
+		cfg.CategoryRoutes = make(map[string]string, len(h.categoryRoutes))
+		for category, webhookURL := range h.categoryRoutes {
+			cfg.CategoryRoutes[category] = redactWebhookURL(webhookURL)
+		}
+	}
+
+	if len(h.levelRoutes) > 0 {
+		cfg.LevelRoutes = make(map[string]string, len(h.levelRoutes))
+		for level, webhookURL := range h.levelRoutes {
+			cfg.LevelRoutes[level.String()] = redactWebhookURL(webhookURL)
+		}
+	}
+
+	return cfg
+}
+
+// redactWebhookURL hides the token of a webhook URL while keeping it recognizable
+// https://discord.com/api/webhooks/123/abc -> https://discord.com/api/webhooks/123/[REDACTED]
+func redactWebhookURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return redactedValue
+	}
+	u.User = nil
+
+	segments := strings.Split(strings.Trim(u.Path, "/"), "/")
+	for i, seg := range segments {
+		// Format Discord: /api/webhooks/{id}/{token}
+		if seg == "webhooks" && i+2 < len(segments) {
+			segments[i+2] = redactedValue
+			segments = segments[:i+3]
+			u.Path = "/" + strings.Join(segments, "/")
+			return unescapeRedacted(u.String())
+		}
+	}
+
+	// URL lain: tampilkan hanya skema dan host
+	return u.Scheme + "://" + u.Host + "/" + redactedValue
+}
+
+// unescapeRedacted keeps the redaction marker readable after URL encoding
+func unescapeRedacted(s string) string {
+	return strings.ReplaceAll(s, url.PathEscape(redactedValue), redactedValue)
+}
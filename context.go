@@ -0,0 +1,68 @@
+package discordrus
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+)
+
+// maxContextBodySize caps how much of a request body is kept in a context for logging
+const maxContextBodySize = 1 << 20 // 1 MB
+
+// requestContextKey is the context key of the request snapshot stored by ContextWithRequest
+type requestContextKey struct{}
+
+// requestSnapshot is a request payload as it was stored, with (the start of) its body
+type requestSnapshot struct {
+	payload LoggerHttpRequestPayload
+	body    []byte
+}
+
+// ContextWithRequest returns a copy of ctx carrying the request payload. Entries logged
+// with that context (logrus WithContext) carry the payload without passing REQUEST_FIELD_KEY:
+//
+//	ctx = discordrus.ContextWithRequest(ctx, discordrus.LoggerHttpRequestPayload{Request: r})
+//	...
+//	logger.WithContext(ctx).WithError(err).Error("failed to create order")
+//
+// The request is snapshotted right away, including up to 1 MB of its body, so it can be
+// logged after the handler has returned. The body of r can still be read completely.
+// A REQUEST_FIELD_KEY field passed explicitly takes precedence over the context.
+func ContextWithRequest(ctx context.Context, p LoggerHttpRequestPayload) context.Context {
+	snap := &requestSnapshot{payload: p}
+	if r := p.Request; r != nil {
+		snap.payload.Request = r.Clone(context.Background())
+		if r.Body != nil && r.Body != http.NoBody {
+			snap.body, _ = io.ReadAll(io.LimitReader(r.Body, maxContextBodySize))
+
+			// Kembalikan body agar handler tetap bisa membaca seluruh isinya
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{io.MultiReader(bytes.NewReader(snap.body), r.Body), r.Body}
+		}
+	}
+	return context.WithValue(ctx, requestContextKey{}, snap)
+}
+
+// RequestFromContext returns the request payload stored by ContextWithRequest in ctx
+// Every call returns a fresh copy, so concurrent entries don't share the body reader.
+func RequestFromContext(ctx context.Context) (LoggerHttpRequestPayload, bool) {
+	if ctx == nil {
+		return LoggerHttpRequestPayload{}, false
+	}
+	snap, ok := ctx.Value(requestContextKey{}).(*requestSnapshot)
+	if !ok {
+		return LoggerHttpRequestPayload{}, false
+	}
+	p := snap.payload
+	if p.Request != nil {
+		p.Request = p.Request.Clone(context.Background())
+		p.Request.Body = http.NoBody
+		if snap.body != nil {
+			p.Request.Body = io.NopCloser(bytes.NewReader(snap.body))
+		}
+	}
+	return p, true
+}
@@ -0,0 +1,118 @@
+package discordrus
+
+import (
+	"net/http"
+	"net/textproto"
+	"sort"
+	"strings"
+)
+
+// curlFile is the name the cURL command is attached as when it does not fit the embed
+const curlFile = "curl.sh"
+
+// WithCurlCommand adds the logged HTTP request as a ready-to-paste curl command to the
+// request embed, built from the redacted method, URL, headers and body. Commands too long
+// for the embed are attached as curl.sh. Multipart bodies are not included.
+func WithCurlCommand() Option {
+	return func(h *Hook) {
+		h.curlCommand = true
+	}
+}
+
+// curlField renders the request as a "cURL" field; body is the captured request body
+// The command is returned as an attachment when it does not fit the field.
+func (h *Hook) curlField(p *LoggerHttpRequestPayload, body []byte) (EmbedField, *Attachment, bool) {
+	if !h.curlCommand {
+		return EmbedField{}, nil, false
+	}
+
+	var method, target string
+	var header http.Header
+	if p.Request != nil {
+		method, target, header = p.Request.Method, h.absoluteRequestURL(p.Request), p.Request.Header
+	} else {
+		method, target, header, body = p.Method, p.URL, parseHeaderLines(p.Headers), []byte(p.BodyString)
+	}
+	if target == "" {
+		return EmbedField{}, nil, false
+	}
+
+	cmd := curlCommand(method, target, header, body)
+	name := h.labelSet().Curl
+	if len([]rune(cmd)) <= h.bodyLimit() {
+		return EmbedField{Name: name, Value: codeBlock("bash", cmd)}, nil, true
+	}
+	field := EmbedField{Name: name, Value: "```Command attached as " + curlFile + " ```"}
+	return field, textAttachment(curlFile, cmd+"\n"), true
+}
+
+// absoluteRequestURL returns the URL of the request including scheme and host, which
+// incoming server requests only carry in the Host header
+func (h *Hook) absoluteRequestURL(r *http.Request) string {
+	target := h.requestURL(r)
+	if strings.HasPrefix(target, "/") && r.Host != "" {
+		scheme := "http"
+		if r.TLS != nil {
+			scheme = "https"
+		}
+		target = scheme + "://" + r.Host + target
+	}
+	return target
+}
+
+// curlCommand builds a curl command line, one option per line
+func curlCommand(method, target string, header http.Header, body []byte) string {
+	if method == "" {
+		method = http.MethodGet
+	}
+	multipart := strings.HasPrefix(header.Get("Content-Type"), "multipart/")
+	note := ""
+	switch {
+	case len(body) > 0 && multipart:
+		note = "# multipart body not included\n"
+	case len(body) > 0 && isBinary(body):
+		note = "# binary body not included\n"
+	}
+
+	lines := []string{"curl -X " + method + " " + shellQuote(target)}
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		// Header yang dihitung ulang oleh curl tidak disertakan
+		switch textproto.CanonicalMIMEHeaderKey(name) {
+		case "Content-Length", "Host", "Connection", "Accept-Encoding":
+			continue
+		case "Content-Type":
+			if multipart {
+				continue
+			}
+		}
+		for _, value := range header[name] {
+			lines = append(lines, "  -H "+shellQuote(name+": "+value))
+		}
+	}
+	if len(body) > 0 && note == "" {
+		lines = append(lines, "  --data-raw "+shellQuote(string(body)))
+	}
+	return note + strings.Join(lines, " \\\n")
+}
+
+// parseHeaderLines parses headers written as "Name: value" lines, as in manually filled payloads
+func parseHeaderLines(s string) http.Header {
+	header := http.Header{}
+	for _, line := range strings.Split(s, "\n") {
+		if name, value, ok := strings.Cut(line, ":"); ok {
+			header.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+		}
+	}
+	return header
+}
+
+// shellQuote quotes s for POSIX shells
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
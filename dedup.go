@@ -0,0 +1,160 @@
+package discordrus
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithDeduplication throttles repeated identical entries: the first entry of a key is
+// delivered right away, repetitions within window are only counted. When the window ends,
+// the delivered message is edited to show e.g. "ERROR (occurred 37 times in 1m0s)".
+// key identifies identical entries, Fingerprint when nil; it is also the fingerprint used for
+// mutes, digests and burst coalescing and shown in the footer, so a key ignoring
+// interpolated IDs groups those entries everywhere. Entries are always told apart by
+// destination.
+//
+// Unlike WithBurstCoalescing, nothing is held back. When the first message was batched
+// or could not be delivered, the counter is posted as a new message instead.
+func WithDeduplication(window time.Duration, key func(entry *logrus.Entry) string) Option {
+	return func(h *Hook) {
+		if window <= 0 {
+			h.dedup = nil
+			return
+		}
+		if key == nil {
+			key = Fingerprint
+		}
+		h.dedup = &deduplicator{h: h, window: window, key: key, records: make(map[string]*dedupRecord)}
+	}
+}
+
+// deduplicator counts repetitions of delivered entries
+type deduplicator struct {
+	h      *Hook
+	window time.Duration
+	key    func(entry *logrus.Entry) string
+
+	mu      sync.Mutex
+	records map[string]*dedupRecord
+}
+
+// dedupRecord is the window of one key: the delivered message and its repetitions
+type dedupRecord struct {
+	mu        sync.Mutex
+	webhook   string
+	count     int
+	firstAt   time.Time
+	last      *logrus.Entry
+	payload   []byte // Payload of the first message, edited when the window ends
+	messageID string
+	threadID  string
+
+	// sent is closed once the delivery of the first message has finished
+	sent     chan struct{}
+	sentOnce sync.Once
+}
+
+// track records the entry and reports whether it repeats a delivered entry
+// It returns the record of a new window, for the message of the entry.
+func (d *deduplicator) track(entry *logrus.Entry, webhookURL string) (*dedupRecord, bool) {
+	if d == nil {
+		return nil, false
+	}
+	if key, _ := d.h.progressKey(entry); key != "" {
+		return nil, false
+	}
+	key := webhookURL + "\x00" + d.key(entry)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if r, ok := d.records[key]; ok {
+		r.mu.Lock()
+		r.count++
+		r.last = entry
+		r.mu.Unlock()
+		return nil, true
+	}
+
+	r := &dedupRecord{webhook: webhookURL, count: 1, firstAt: time.Now(), last: entry, sent: make(chan struct{})}
+	d.records[key] = r
+	d.h.pending.add()
+	time.AfterFunc(d.window, func() { d.flush(key, r) })
+	return r, false
+}
+
+// delivered remembers the message Discord created for the first entry of the window
+func (r *dedupRecord) delivered(m *message, resp []byte) {
+	created, err := createdMessage(resp)
+	if err != nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.payload = m.Payload
+	r.messageID = created.ID
+	r.threadID = m.createdThreadID(created)
+}
+
+// release marks the delivery of the first message as finished, successful or not
+func (r *dedupRecord) release() {
+	if r != nil {
+		r.sentOnce.Do(func() { close(r.sent) })
+	}
+}
+
+// flush ends the window and shows the number of occurrences, if the entry repeated
+func (d *deduplicator) flush(key string, r *dedupRecord) {
+	d.mu.Lock()
+	if d.records[key] != r {
+		d.mu.Unlock()
+		return
+	}
+	delete(d.records, key)
+	d.mu.Unlock()
+	defer d.h.pending.done()
+
+	// Tunggu pengiriman pesan pertama agar ID-nya sudah diketahui
+	<-r.sent
+	r.mu.Lock()
+	count, elapsed, last := r.count, time.Since(r.firstAt).Round(time.Millisecond), r.last
+	m := &message{URL: r.webhook, Payload: r.payload, editID: r.messageID, threadID: r.threadID, entry: last}
+	r.mu.Unlock()
+	if count < 2 {
+		return
+	}
+	suffix := fmt.Sprintf(" (occurred %d times in %s)", count, elapsed)
+
+	if m.editID == "" {
+		// ID pesan pertama tidak diketahui, jadi kirim pesan baru dari entry terakhir
+		built, err := d.h.prepare(last, r.webhook)
+		if err == nil {
+			d.h.annotateTitle(built, suffix)
+			err = d.h.deliver(built)
+		}
+		if err != nil {
+			d.h.reportError(err, last)
+		}
+		return
+	}
+	d.h.annotateTitle(m, suffix)
+	if _, err := d.h.send(m); err != nil {
+		d.h.reportError(err, last)
+	}
+}
+
+// flushAll ends every running window immediately
+func (d *deduplicator) flushAll() {
+	d.mu.Lock()
+	records := make(map[string]*dedupRecord, len(d.records))
+	for key, r := range d.records {
+		records[key] = r
+	}
+	d.mu.Unlock()
+
+	for key, r := range records {
+		d.flush(key, r)
+	}
+}
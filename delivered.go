@@ -0,0 +1,83 @@
+package discordrus
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// DeliveryResult describes a message Discord accepted, see Hook.OnDelivered
+type DeliveryResult struct {
+	WebhookURL string        // Redacted webhook URL
+	MessageID  string        // ID of the created (or edited) message
+	ChannelID  string        // Channel of the message, the thread for messages posted into a thread
+	ThreadID   string        // Thread the message was posted into, if any
+	GuildID    string        // Server of the webhook, empty when it could not be looked up
+	URL        string        // Link to the message, empty when the server is unknown
+	Attempts   int           // Number of requests sent
+	Latency    time.Duration // Duration of the last request
+}
+
+// webhookObject is the part of a webhook object needed to link to its messages
+type webhookObject struct {
+	GuildID string `json:"guild_id"`
+}
+
+// reportDelivered passes the message Discord created to OnDelivered, once for every
+// entry the message was built from
+func (h *Hook) reportDelivered(m *message, resp []byte, attempts int, latency time.Duration) {
+	created, err := createdMessage(resp)
+	if err != nil {
+		return
+	}
+
+	result := DeliveryResult{
+		WebhookURL: redactWebhookURL(m.URL),
+		MessageID:  created.ID,
+		ChannelID:  created.ChannelID,
+		ThreadID:   m.createdThreadID(created),
+		GuildID:    h.guildOf(m.URL),
+		Attempts:   attempts,
+		Latency:    latency,
+	}
+	if result.GuildID != "" && result.ChannelID != "" {
+		result.URL = "https://discord.com/channels/" + result.GuildID + "/" + result.ChannelID + "/" + result.MessageID
+	}
+	for _, entry := range m.entries() {
+		h.OnDelivered(result, entry)
+	}
+}
+
+// guildOf returns the server of the webhook, fetching the webhook object once
+func (h *Hook) guildOf(webhookURL string) string {
+	d := h.destinationFor(webhookURL)
+	d.guildOnce.Do(func() {
+		d.guildID = h.fetchGuildID(webhookURL)
+	})
+	return d.guildID
+}
+
+// fetchGuildID fetches the webhook object, see https://discord.com/developers/docs/resources/webhook
+func (h *Hook) fetchGuildID(webhookURL string) string {
+	respons, body, err := h.sender().Get(context.Background(), webhookURL, webhookURL)
+	if err != nil || respons.StatusCode >= 300 {
+		return ""
+	}
+
+	var webhook webhookObject
+	_ = json.Unmarshal(body, &webhook)
+	return webhook.GuildID
+}
+
+// entries returns the log entries the message was built from
+func (m *message) entries() []*logrus.Entry {
+	if m.batched != nil {
+		return m.batched
+	}
+	if m.entry != nil {
+		return []*logrus.Entry{m.entry}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package discordrus
+
+import (
+	"context"
+
+	"github.com/murbagus/discordrus/sender"
+)
+
+// Sender delivers the built webhook messages. The default posts them to Discord with
+// retries and rate limiting (*sender.Sender); other implementations deliver the same
+// payload elsewhere, e.g. the Slack sender of package slack.
+type Sender interface {
+	Send(ctx context.Context, m *sender.Message) (sender.Result, error)
+}
+
+// WithSender delivers messages with s instead of posting them to Discord, see Sender.
+// The payload reaches s as Discord embeds, WithComponentsV2 only applies to Discord.
+// Features reading sent messages back from Discord (WithDeliveryVerification,
+// WithHealthProbe, edits of deduplicated and progress messages) need a sender returning
+// Discord's responses.
+func WithSender(s Sender) Option {
+	return func(h *Hook) {
+		h.customSender = s
+	}
+}
+
+// delivery returns the sender messages are delivered with
+func (h *Hook) delivery() Sender {
+	if h.customSender != nil {
+		return h.customSender
+	}
+	return h.sender()
+}
@@ -0,0 +1,196 @@
+package discordrus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/sirupsen/logrus"
+)
+
+// DiffKind describes how a value differs between two payloads
+type DiffKind string
+
+const (
+	DiffAdded     DiffKind = "added"     // present only in the new payload
+	DiffRemoved   DiffKind = "removed"   // present only in the old payload
+	DiffChanged   DiffKind = "changed"   // present in both with different values
+	DiffTruncated DiffKind = "truncated" // strings where one is the other cut off with an ellipsis
+)
+
+// PayloadDifference is a single difference between two payloads
+type PayloadDifference struct {
+	Path string // JSON path, e.g. embeds[1].fields[0].value
+	Kind DiffKind
+	Old  any
+	New  any
+}
+
+// String returns a human readable description of the difference
+func (d PayloadDifference) String() string {
+	switch d.Kind {
+	case DiffAdded:
+		return fmt.Sprintf("%s: added %s", d.Path, describeValue(d.New))
+	case DiffRemoved:
+		return fmt.Sprintf("%s: removed %s", d.Path, describeValue(d.Old))
+	case DiffTruncated:
+		return fmt.Sprintf("%s: truncated differently (%d → %d chars)", d.Path,
+			len([]rune(d.Old.(string))), len([]rune(d.New.(string))))
+	default:
+		return fmt.Sprintf("%s: changed from %s to %s", d.Path, describeValue(d.Old), describeValue(d.New))
+	}
+}
+
+// previewKey marks the context of entries built by BuildPayload
+type previewKey struct{}
+
+// isPreview reports whether ctx belongs to an entry built by BuildPayload
+func isPreview(ctx context.Context) bool {
+	return ctx != nil && ctx.Value(previewKey{}) != nil
+}
+
+// BuildPayload renders the entry into the JSON payload the hook would send, without sending it.
+// It is meant for golden-file comparisons together with DiffPayloads. Building has no side
+// effects: no goroutine dump or system snapshot is taken, attachments are not read and
+// errors (e.g. of templates) are not passed to OnError.
+func (h *Hook) BuildPayload(entry *logrus.Entry) ([]byte, error) {
+	if err := validateEntry(entry); err != nil {
+		return nil, err
+	}
+	snapshot := h.copyEntry(entry)
+	ctx := snapshot.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	snapshot.Context = context.WithValue(ctx, previewKey{}, true)
+
+	payload, err := h.buildPayload(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := h.newMessage(payload)
+	if err != nil {
+		return nil, err
+	}
+	return msg.Payload, nil
+}
+
+// DiffPayloads compares two built webhook payloads (e.g. current vs golden)
+// and returns the differences ordered by path. An empty result means both are equal.
+func DiffPayloads(oldPayload, newPayload []byte) ([]PayloadDifference, error) {
+	var a, b any
+	if err := json.Unmarshal(oldPayload, &a); err != nil {
+		return nil, eris.Wrap(err, "invalid old payload")
+	}
+	if err := json.Unmarshal(newPayload, &b); err != nil {
+		return nil, eris.Wrap(err, "invalid new payload")
+	}
+
+	var diffs []PayloadDifference
+	diffValues("", a, b, &diffs)
+	return diffs, nil
+}
+
+func diffValues(path string, a, b any, diffs *[]PayloadDifference) {
+	switch av := a.(type) {
+	case map[string]any:
+		if bv, ok := b.(map[string]any); ok {
+			keys := make([]string, 0, len(av)+len(bv))
+			for k := range av {
+				keys = append(keys, k)
+			}
+			for k := range bv {
+				if _, ok := av[k]; !ok {
+					keys = append(keys, k)
+				}
+			}
+			sort.Strings(keys)
+
+			for _, k := range keys {
+				p := k
+				if path != "" {
+					p = path + "." + k
+				}
+				ov, inOld := av[k]
+				nv, inNew := bv[k]
+				switch {
+				case !inNew:
+					*diffs = append(*diffs, PayloadDifference{Path: p, Kind: DiffRemoved, Old: ov})
+				case !inOld:
+					*diffs = append(*diffs, PayloadDifference{Path: p, Kind: DiffAdded, New: nv})
+				default:
+					diffValues(p, ov, nv, diffs)
+				}
+			}
+			return
+		}
+
+	case []any:
+		if bv, ok := b.([]any); ok {
+			for i := 0; i < len(av) || i < len(bv); i++ {
+				p := fmt.Sprintf("%s[%d]", path, i)
+				switch {
+				case i >= len(bv):
+					*diffs = append(*diffs, PayloadDifference{Path: p, Kind: DiffRemoved, Old: av[i]})
+				case i >= len(av):
+					*diffs = append(*diffs, PayloadDifference{Path: p, Kind: DiffAdded, New: bv[i]})
+				default:
+					diffValues(p, av[i], bv[i], diffs)
+				}
+			}
+			return
+		}
+
+	case string:
+		if bv, ok := b.(string); ok {
+			if av == bv {
+				return
+			}
+			if isTruncationOf(av, bv) || isTruncationOf(bv, av) {
+				*diffs = append(*diffs, PayloadDifference{Path: path, Kind: DiffTruncated, Old: av, New: bv})
+				return
+			}
+		}
+	}
+
+	if !reflect.DeepEqual(a, b) {
+		*diffs = append(*diffs, PayloadDifference{Path: path, Kind: DiffChanged, Old: a, New: b})
+	}
+}
+
+// isTruncationOf reports whether short is a truncated version of long: a prefix of long
+// followed by an ellipsis marker, and the code fence closed by the truncation if any.
+// A string that is merely shorter is not a truncation.
+func isTruncationOf(short, long string) bool {
+	s := strings.TrimSuffix(strings.TrimSuffix(short, "```"), " ")
+	for _, marker := range []string{"…", "..."} {
+		if kept, ok := strings.CutSuffix(s, marker); ok {
+			return len(kept) < len(long) && strings.HasPrefix(long, kept)
+		}
+	}
+	return false
+}
+
+// describeValue renders a JSON value for a difference report
+func describeValue(v any) string {
+	switch val := v.(type) {
+	case map[string]any:
+		if name, ok := val["name"].(string); ok {
+			return fmt.Sprintf("field %q", name)
+		}
+		if title, ok := val["title"].(string); ok {
+			return fmt.Sprintf("embed %q", title)
+		}
+		return fmt.Sprintf("object with %d key(s)", len(val))
+	case []any:
+		return fmt.Sprintf("list of %d item(s)", len(val))
+	case string:
+		return fmt.Sprintf("%q", previewText(val, 60))
+	default:
+		return fmt.Sprintf("%v", val)
+	}
+}
@@ -0,0 +1,62 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestIsTruncationOf(t *testing.T) {
+	tests := []struct {
+		name        string
+		short, long string
+		want        bool
+	}{
+		{"ellipsis", "hello wo…", "hello world", true},
+		{"dots", "hello wo...", "hello world", true},
+		{"code block", "```hello wo… ```", "```hello world ```", true},
+		{"code block without space", "```hello wo…```", "```hello world```", true},
+		{"merely shorter", "hello", "hello world", false},
+		{"different text", "goodbye…", "hello world", false},
+		{"longer than original", "hello world!…", "hello world", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTruncationOf(tt.short, tt.long); got != tt.want {
+				t.Errorf("isTruncationOf(%q, %q) = %v, want %v", tt.short, tt.long, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffPayloadsShorterIsChanged(t *testing.T) {
+	diffs, err := DiffPayloads([]byte(`{"content":"disk full on db-1"}`), []byte(`{"content":"disk full"}`))
+	if err != nil {
+		t.Fatalf("DiffPayloads: %v", err)
+	}
+	if len(diffs) != 1 || diffs[0].Kind != DiffChanged {
+		t.Errorf("DiffPayloads() = %v, want one %q difference", diffs, DiffChanged)
+	}
+}
+
+func TestBuildPayloadHasNoSideEffects(t *testing.T) {
+	h := NewHook("https://discord.com/api/webhooks/1/token",
+		WithGoroutineDump(), WithFieldTemplate("Broken", "{{index .Fields.items 5}}"))
+	h.OnError = func(err error, _ *logrus.Entry) { t.Errorf("BuildPayload reported %v", err) }
+
+	entry := &logrus.Entry{
+		Logger:  logrus.New(),
+		Level:   logrus.FatalLevel,
+		Message: "worker crashed",
+		Data:    logrus.Fields{"items": []int{1}},
+	}
+	payload, err := h.BuildPayload(entry)
+	if err != nil {
+		t.Fatalf("BuildPayload: %v", err)
+	}
+	if !json.Valid(payload) || !strings.Contains(string(payload), "worker crashed") {
+		t.Errorf("BuildPayload() = %s, want the rendered entry", payload)
+	}
+}
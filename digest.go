@@ -0,0 +1,219 @@
+package discordrus
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxDigestLines is the number of fingerprints listed in a digest
+const maxDigestLines = 20
+
+// digest counts entries per fingerprint and sends them as one summary per interval
+type digest struct {
+	interval time.Duration
+	levels   map[logrus.Level]bool
+	stop     chan struct{}
+	stopOnce sync.Once
+
+	mu       sync.Mutex
+	current  map[string]*digestEntry
+	previous map[string]int // jumlah per fingerprint pada interval sebelumnya
+}
+
+// digestEntry is a fingerprint counted in the current interval
+type digestEntry struct {
+	key     string
+	url     string
+	level   logrus.Level
+	message string
+	count   int
+}
+
+// WithDigest sends entries of the given levels (all hook levels when none are given) as a
+// digest every interval instead of one message each. The digest lists every fingerprint
+// per destination with its count and the trend against the previous interval, e.g. "↑ 30%",
+// so it communicates direction, not just totals. A final digest is sent when the hook is closed.
+func WithDigest(interval time.Duration, levels ...logrus.Level) Option {
+	return func(h *Hook) {
+		if interval <= 0 {
+			h.digest = nil
+			return
+		}
+		d := &digest{interval: interval, stop: make(chan struct{}), current: make(map[string]*digestEntry)}
+		if len(levels) > 0 {
+			d.levels = make(map[logrus.Level]bool, len(levels))
+			for _, level := range levels {
+				d.levels[level] = true
+			}
+		}
+		h.digest = d
+	}
+}
+
+// covers reports whether entries of the level go into the digest
+func (d *digest) covers(level logrus.Level) bool {
+	return d.levels == nil || d.levels[level]
+}
+
+// add counts the entry for the next digest under its fingerprint, see Hook.fingerprint
+func (d *digest) add(entry *logrus.Entry, webhookURL, fingerprint string) {
+	errText := ""
+	if err, ok := entry.Data[logrus.ErrorKey].(error); ok && err != nil {
+		errText = err.Error()
+	}
+	message := entry.Message
+	if errText != "" {
+		message += ": " + errText
+	}
+	key := webhookURL + "\x00" + fingerprint
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	e, ok := d.current[key]
+	if !ok {
+		e = &digestEntry{key: key, url: webhookURL, level: entry.Level, message: message}
+		d.current[key] = e
+	}
+	e.count++
+}
+
+// rotate returns the counts of the interval that just ended, together with the counts
+// of the interval before, and starts a new interval
+func (d *digest) rotate() (map[string]*digestEntry, map[string]int) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	current, previous := d.current, d.previous
+	d.previous = make(map[string]int, len(current))
+	for key, e := range current {
+		d.previous[key] = e.count
+	}
+	d.current = make(map[string]*digestEntry)
+	return current, previous
+}
+
+// startDigest sends a digest every interval until the hook is closed
+func (h *Hook) startDigest() {
+	go func() {
+		ticker := time.NewTicker(h.digest.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				h.sendDigest()
+			case <-h.digest.stop:
+				return
+			}
+		}
+	}()
+}
+
+// stopDigest stops the digest timer and sends the entries counted so far
+func (h *Hook) stopDigest() {
+	if h.digest == nil {
+		return
+	}
+	stopped := false
+	h.digest.stopOnce.Do(func() {
+		close(h.digest.stop)
+		stopped = true
+	})
+	if stopped {
+		h.sendDigest()
+	}
+}
+
+// sendDigest sends one digest message per destination for the interval that just ended
+func (h *Hook) sendDigest() {
+	current, previous := h.digest.rotate()
+
+	byURL := make(map[string][]*digestEntry)
+	for _, e := range current {
+		byURL[e.url] = append(byURL[e.url], e)
+	}
+	for webhookURL, entries := range byURL {
+		quiet := 0
+		for key, prev := range previous {
+			if _, ok := current[key]; !ok && prev > 0 && strings.HasPrefix(key, webhookURL+"\x00") {
+				quiet++
+			}
+		}
+		payload := h.digestPayload(entries, previous, quiet)
+		if h.threadName != nil {
+			// Webhook forum wajib membuat thread
+			payload.ThreadName = "Digest"
+		}
+
+		msg, err := h.newMessage(payload)
+		if err != nil {
+			h.reportError(err, nil)
+			continue
+		}
+		msg.URL = webhookURL
+
+		h.pending.add()
+		go func() {
+			defer h.pending.done()
+			if err := h.deliver(msg); err != nil {
+				h.reportError(err, nil)
+			}
+		}()
+	}
+}
+
+// digestPayload renders the digest of one destination, most frequent entries first
+func (h *Hook) digestPayload(entries []*digestEntry, previous map[string]int, quiet int) *WebhookPayload {
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].count != entries[j].count {
+			return entries[i].count > entries[j].count
+		}
+		return entries[i].level < entries[j].level
+	})
+
+	total, highest := 0, logrus.TraceLevel
+	var b strings.Builder
+	for i, e := range entries {
+		total += e.count
+		if e.level < highest {
+			highest = e.level
+		}
+		if i == maxDigestLines {
+			fmt.Fprintf(&b, "… %d more\n", len(entries)-i)
+			continue
+		}
+		if i > maxDigestLines {
+			continue
+		}
+		fmt.Fprintf(&b, "`%d` %s **%s** %s\n", e.count, trendIndicator(e.count, previous[e.key]),
+			strings.ToUpper(e.level.String()), previewText(markdownEscaper.Replace(e.message), maxPreviewLength/3))
+	}
+	if quiet > 0 {
+		fmt.Fprintf(&b, "%d of the previous digest's entries did not recur\n", quiet)
+	}
+
+	return &WebhookPayload{Embeds: []Embed{{
+		Title:       fmt.Sprintf("DIGEST — %d entries in the last %s", total, h.digest.interval),
+		Description: strings.TrimRight(b.String(), "\n"),
+		Timestamp:   time.Now().UTC().Format(time.RFC3339),
+		Color:       h.colorFor(highest),
+	}}}
+}
+
+// trendIndicator compares the count of a fingerprint with the previous interval
+func trendIndicator(count, previous int) string {
+	switch {
+	case previous == 0:
+		return "🆕"
+	case count > previous:
+		return fmt.Sprintf("↑ %d%%", (count-previous)*100/previous)
+	case count < previous:
+		return fmt.Sprintf("↓ %d%%", (previous-count)*100/previous)
+	default:
+		return "→ 0%"
+	}
+}
@@ -0,0 +1,58 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestDigestGroupsByFingerprint(t *testing.T) {
+	var mu sync.Mutex
+	var digests []WebhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p WebhookPayload
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &p)
+		mu.Lock()
+		digests = append(digests, p)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	// Key tanpa ID di dalam pesan, sehingga "order 1 failed" dan "order 2 failed" dikelompokkan
+	digits := regexp.MustCompile(`\d+`)
+	key := func(e *logrus.Entry) string { return digits.ReplaceAllString(e.Message, "N") }
+	h := NewHook(srv.URL, WithDigest(365*24*time.Hour), WithDeduplication(time.Minute, key))
+
+	logger := logrus.New()
+	for _, msg := range []string{"order 1 failed", "order 2 failed", "order 3 failed", "cache miss"} {
+		if err := h.Fire(errorEntry(logger, msg)); err != nil {
+			t.Fatalf("Fire: %v", err)
+		}
+	}
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(digests) != 1 || len(digests[0].Embeds) != 1 {
+		t.Fatalf("got %d digest messages, want 1", len(digests))
+	}
+	lines := strings.Split(digests[0].Embeds[0].Description, "\n")
+	if len(lines) != 2 {
+		t.Fatalf("digest lists %d lines, want 2 fingerprints:\n%s", len(lines), digests[0].Embeds[0].Description)
+	}
+	if !strings.HasPrefix(lines[0], "`3`") || !strings.Contains(lines[0], "order 1 failed") {
+		t.Errorf("first digest line = %q, want the 3 grouped order entries", lines[0])
+	}
+}
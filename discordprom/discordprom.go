@@ -0,0 +1,94 @@
+// Package discordprom exports the delivery metrics of a discordrus hook to Prometheus.
+//
+// Usage:
+//
+//	prometheus.MustRegister(discordprom.NewCollector(hook))
+//
+// Alert on e.g. rate(discordrus_messages_failed_total[5m]) > 0.
+package discordprom
+
+import (
+	"github.com/murbagus/discordrus"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector reports the metrics of a hook on every scrape
+type Collector struct {
+	hook        *discordrus.Hook
+	sent        *prometheus.Desc
+	failed      *prometheus.Desc
+	retried     *prometheus.Desc
+	rateLimited *prometheus.Desc
+	dropped     *prometheus.Desc
+	latency     *prometheus.Desc
+}
+
+// Option configures a Collector
+type Option func(*options)
+
+type options struct {
+	namespace   string
+	constLabels prometheus.Labels
+}
+
+// WithNamespace sets the prefix of the metric names (default "discordrus")
+func WithNamespace(namespace string) Option {
+	return func(o *options) {
+		o.namespace = namespace
+	}
+}
+
+// WithConstLabels adds labels to every metric, e.g. to tell several hooks apart
+func WithConstLabels(labels prometheus.Labels) Option {
+	return func(o *options) {
+		o.constLabels = labels
+	}
+}
+
+// NewCollector creates a collector for the hook
+func NewCollector(hook *discordrus.Hook, opts ...Option) *Collector {
+	o := &options{namespace: "discordrus"}
+	for _, opt := range opts {
+		opt(o)
+	}
+	desc := func(name, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(o.namespace, "", name), help, nil, o.constLabels)
+	}
+
+	return &Collector{
+		hook:        hook,
+		sent:        desc("messages_sent_total", "Messages delivered to Discord."),
+		failed:      desc("messages_failed_total", "Messages that could not be delivered to Discord."),
+		retried:     desc("requests_retried_total", "Requests to Discord repeated after a transient failure or rate limit."),
+		rateLimited: desc("requests_rate_limited_total", "Requests to Discord answered with HTTP 429."),
+		dropped:     desc("entries_dropped_total", "Log entries dropped because the delivery queue was full."),
+		latency:     desc("delivery_duration_seconds", "Duration of delivered requests to Discord."),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{c.sent, c.failed, c.retried, c.rateLimited, c.dropped, c.latency} {
+		ch <- d
+	}
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	m := c.hook.Metrics()
+
+	counter := func(d *prometheus.Desc, v uint64) {
+		ch <- prometheus.MustNewConstMetric(d, prometheus.CounterValue, float64(v))
+	}
+	counter(c.sent, m.Sent)
+	counter(c.failed, m.Failed)
+	counter(c.retried, m.Retried)
+	counter(c.rateLimited, m.RateLimited)
+	counter(c.dropped, m.Dropped)
+
+	buckets := make(map[float64]uint64, len(discordrus.LatencyBuckets))
+	for i, bound := range discordrus.LatencyBuckets {
+		buckets[bound.Seconds()] = m.LatencyCounts[i]
+	}
+	ch <- prometheus.MustNewConstHistogram(c.latency, m.LatencyCount, m.LatencySum.Seconds(), buckets)
+}
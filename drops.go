@@ -0,0 +1,127 @@
+package discordrus
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// dropReportDelay is the time dropped entries are collected before they are reported
+const dropReportDelay = time.Minute
+
+// WithoutDropReports stops posting "N log entries were dropped" messages when the queue
+// overflows, see WithWorkerPool. DroppedCount still counts dropped entries.
+func WithoutDropReports() Option {
+	return func(h *Hook) {
+		h.drops.disabled = true
+	}
+}
+
+// dropCounter counts the entries dropped because the queue was full
+type dropCounter struct {
+	disabled bool
+
+	mu        sync.Mutex
+	total     uint64
+	unsent    map[string]uint64 // jumlah per webhook yang belum dilaporkan
+	scheduled bool
+	timer     *time.Timer
+	stopped   bool // hook ditutup, laporan tidak dijadwalkan lagi
+}
+
+// DroppedCount returns the number of asynchronous entries dropped because the queue was full
+func (h *Hook) DroppedCount() uint64 {
+	h.drops.mu.Lock()
+	defer h.drops.mu.Unlock()
+	return h.drops.total
+}
+
+// countDrop counts a dropped entry and schedules its report
+func (h *Hook) countDrop(webhookURL string) {
+	h.drops.mu.Lock()
+	defer h.drops.mu.Unlock()
+
+	h.drops.total++
+	if h.drops.disabled || webhookURL == "" {
+		return
+	}
+	if h.drops.unsent == nil {
+		h.drops.unsent = make(map[string]uint64)
+	}
+	h.drops.unsent[webhookURL]++
+	if !h.drops.scheduled && !h.drops.stopped {
+		h.drops.scheduled = true
+		h.drops.timer = time.AfterFunc(dropReportDelay, h.sendDropReports)
+	}
+}
+
+// sendDropReports posts the number of entries dropped since the last report to each webhook
+func (h *Hook) sendDropReports() {
+	for _, msg := range h.dropReports() {
+		h.pending.add()
+		go func() {
+			defer h.pending.done()
+			if err := h.deliver(msg); err != nil {
+				h.reportError(err, nil)
+			}
+		}()
+	}
+}
+
+// stopDropReports stops the scheduled report and sends the entries dropped so far before
+// it returns, see Hook.Close
+func (h *Hook) stopDropReports() {
+	h.drops.mu.Lock()
+	h.drops.stopped = true
+	if h.drops.timer != nil {
+		h.drops.timer.Stop()
+	}
+	h.drops.mu.Unlock()
+
+	for _, msg := range h.dropReports() {
+		if err := h.deliver(msg); err != nil {
+			h.reportError(err, nil)
+		}
+	}
+}
+
+// dropReports returns the report messages of the entries dropped since the last report
+func (h *Hook) dropReports() []*message {
+	h.drops.mu.Lock()
+	unsent := h.drops.unsent
+	h.drops.unsent = nil
+	h.drops.scheduled = false
+	h.drops.mu.Unlock()
+
+	urls := make([]string, 0, len(unsent))
+	for webhookURL := range unsent {
+		urls = append(urls, webhookURL)
+	}
+	sort.Strings(urls)
+
+	var reports []*message
+	for _, webhookURL := range urls {
+		payload := &WebhookPayload{Embeds: []Embed{{
+			Title:       "DROPPED ENTRIES",
+			Description: fmt.Sprintf("**%d** log entries were dropped because the delivery queue was full", unsent[webhookURL]),
+			Timestamp:   time.Now().UTC().Format(time.RFC3339),
+			Color:       h.colorFor(logrus.WarnLevel),
+		}}}
+		if h.threadName != nil {
+			// Webhook forum wajib membuat thread
+			payload.ThreadName = "Dropped entries"
+		}
+
+		msg, err := h.newMessage(payload)
+		if err != nil {
+			h.reportError(err, nil)
+			continue
+		}
+		msg.URL = webhookURL
+		reports = append(reports, msg)
+	}
+	return reports
+}
@@ -0,0 +1,33 @@
+package discordrus
+
+import (
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestCloseSendsPendingDropReport(t *testing.T) {
+	srv, posts := countingServer(t, 0)
+	h := NewHook(srv.URL)
+	h.OnError = func(err error, _ *logrus.Entry) { t.Errorf("delivery failed: %v", err) }
+
+	h.countDrop(srv.URL)
+	h.countDrop(srv.URL)
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := posts.Load(); got != 1 {
+		t.Errorf("posted %d drop reports on Close, want 1", got)
+	}
+
+	// Entry yang di-drop setelah Close tidak menjadwalkan laporan lewat hook yang sudah ditutup
+	h.countDrop(srv.URL)
+	h.drops.mu.Lock()
+	defer h.drops.mu.Unlock()
+	if h.drops.scheduled {
+		t.Error("drop report scheduled after Close")
+	}
+	if h.drops.total != 3 {
+		t.Errorf("counted %d drops, want 3", h.drops.total)
+	}
+}
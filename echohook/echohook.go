@@ -0,0 +1,68 @@
+// Package echohook provides an Echo recovery middleware that reports panics through a
+// logger with the discordrus hook, including the request, its path parameters and the
+// client IP. Entries logged with the request context carry the request as well.
+//
+// Usage:
+//
+//	e := echo.New()
+//	e.Use(echohook.Recovery(logger))
+//
+//	e.POST("/orders/:id", func(c echo.Context) error {
+//		logger.WithContext(c.Request().Context()).WithError(err).Error("failed to update order")
+//		return err
+//	})
+package echohook
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+	"github.com/murbagus/discordrus"
+	"github.com/sirupsen/logrus"
+)
+
+// Recovery stores the request in the request context and recovers panics of later
+// handlers: they are logged at error level and answered with status 500
+func Recovery(logger *logrus.Logger) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) (err error) {
+			c.SetRequest(discordrus.CaptureRequest(c.Request()))
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				// Koneksi sengaja diputus oleh handler, biarkan server yang menanganinya
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+
+				logger.WithFields(logrus.Fields{
+					logrus.ErrorKey:              discordrus.PanicError(v),
+					discordrus.REQUEST_FIELD_KEY: Payload(c),
+				}).Errorf("panic serving %s %s", c.Request().Method, c.Path())
+				err = echo.NewHTTPError(http.StatusInternalServerError)
+			}()
+			return next(c)
+		}
+	}
+}
+
+// Payload returns the request payload of c with its path parameters and client IP
+func Payload(c echo.Context) discordrus.LoggerHttpRequestPayload {
+	p, ok := discordrus.RequestFromContext(c.Request().Context())
+	if !ok {
+		p = discordrus.LoggerHttpRequestPayload{Request: c.Request()}
+	}
+	p.ClientIP = c.RealIP()
+	if names := c.ParamNames(); len(names) > 0 {
+		values := c.ParamValues()
+		p.PathParams = make(map[string]string, len(names))
+		for i, name := range names {
+			if i < len(values) {
+				p.PathParams[name] = values[i]
+			}
+		}
+	}
+	return p
+}
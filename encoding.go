@@ -0,0 +1,66 @@
+package discordrus
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// maxDecodedBodySize caps how much of a compressed body is decompressed for logging
+const maxDecodedBodySize = 1 << 20 // 1 MB
+
+// decodeBody decodes a body according to its Content-Encoding header (gzip and deflate),
+// so compressed request bodies are rendered as text. At most 1 MB is decompressed, a body
+// cut short (e.g. by the 1 MB limit of ContextWithRequest) is decoded as far as possible.
+// Bodies in another encoding, such as br, are replaced by a short note. It reports
+// whether the body was changed.
+func decodeBody(body []byte, contentEncoding string) ([]byte, bool) {
+	if contentEncoding == "" || len(body) == 0 {
+		return body, false
+	}
+
+	// Encoding dengan beberapa tahap dibuka dari yang terakhir diterapkan
+	encodings := strings.Split(contentEncoding, ",")
+	decoded := body
+	for i := len(encodings) - 1; i >= 0; i-- {
+		encoding := strings.ToLower(strings.TrimSpace(encodings[i]))
+		var err error
+		switch encoding {
+		case "", "identity":
+			continue
+		case "gzip", "x-gzip":
+			decoded, err = decompress(decoded, func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) })
+		case "deflate":
+			// Sesuai spesifikasi deflate dibungkus zlib, sebagian klien mengirim deflate mentah
+			raw := decoded
+			decoded, err = decompress(raw, func(r io.Reader) (io.Reader, error) { return zlib.NewReader(r) })
+			if err != nil {
+				decoded, err = decompress(raw, func(r io.Reader) (io.Reader, error) { return flate.NewReader(r), nil })
+			}
+		default:
+			return []byte(fmt.Sprintf("(%s %s-encoded body, not decoded)", formatBytes(len(body)), encoding)), true
+		}
+		if err != nil {
+			return []byte(fmt.Sprintf("(%s %s-encoded body, failed to decode: %v)", formatBytes(len(body)), encoding, err)), true
+		}
+	}
+	return decoded, true
+}
+
+// decompress reads up to maxDecodedBodySize bytes from the reader returned by open
+// Data decoded before the input ended early is returned without an error.
+func decompress(body []byte, open func(io.Reader) (io.Reader, error)) ([]byte, error) {
+	r, err := open(bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	decoded, err := io.ReadAll(io.LimitReader(r, maxDecodedBodySize))
+	if err == io.ErrUnexpectedEOF && len(decoded) > 0 {
+		err = nil
+	}
+	return decoded, err
+}
@@ -0,0 +1,69 @@
+package discordrus
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ErrInvalidEntry is returned for entries that cannot be rendered, e.g. a nil entry or
+// an unknown level. Other gaps of manually built entries are filled with defaults.
+type ErrInvalidEntry struct {
+	Reason string
+}
+
+func (e *ErrInvalidEntry) Error() string {
+	return "invalid log entry: " + e.Reason
+}
+
+// validateEntry reports entries that cannot be rendered
+func validateEntry(entry *logrus.Entry) error {
+	if entry == nil {
+		return &ErrInvalidEntry{Reason: "entry is nil"}
+	}
+	if entry.Level > logrus.TraceLevel {
+		return &ErrInvalidEntry{Reason: fmt.Sprintf("unknown level %d", entry.Level)}
+	}
+	return nil
+}
+
+// normalizeEntry fills the gaps of manually built entries in place: a nil Data map,
+// a zero Time (the entry is dated now) and a nil pointer passed as error
+func normalizeEntry(entry *logrus.Entry) {
+	if entry.Data == nil {
+		entry.Data = logrus.Fields{}
+	}
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+	if nilError(entry) {
+		entry.Data[logrus.ErrorKey] = fmt.Sprintf("<nil %T>", entry.Data[logrus.ErrorKey])
+	}
+}
+
+// normalizedEntry returns the entry, or a normalized copy if it has gaps, see normalizeEntry
+func normalizedEntry(entry *logrus.Entry) *logrus.Entry {
+	if entry.Data != nil && !entry.Time.IsZero() && !nilError(entry) {
+		return entry
+	}
+	copied := *entry
+	copied.Data = make(logrus.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		copied.Data[k] = v
+	}
+	normalizeEntry(&copied)
+	return &copied
+}
+
+// nilError reports whether the error of the entry is a nil pointer, whose Error method
+// usually panics
+func nilError(entry *logrus.Entry) bool {
+	err, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok {
+		return false
+	}
+	v := reflect.ValueOf(err)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
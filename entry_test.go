@@ -0,0 +1,122 @@
+package discordrus
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type nilPointerError struct{}
+
+func (*nilPointerError) Error() string { return "never called on nil" }
+
+func TestValidateEntry(t *testing.T) {
+	tests := []struct {
+		name    string
+		entry   *logrus.Entry
+		wantErr bool
+	}{
+		{"nil entry", nil, true},
+		{"nil data", &logrus.Entry{Level: logrus.ErrorLevel}, false},
+		{"invalid level", &logrus.Entry{Level: logrus.TraceLevel + 1, Data: logrus.Fields{}}, true},
+		{"trace level", &logrus.Entry{Level: logrus.TraceLevel, Data: logrus.Fields{}}, false},
+		{"panic level", &logrus.Entry{Level: logrus.PanicLevel, Data: logrus.Fields{}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateEntry(tt.entry)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("validateEntry() = %v, wantErr %v", err, tt.wantErr)
+			}
+			var invalid *ErrInvalidEntry
+			if err != nil && !errors.As(err, &invalid) {
+				t.Errorf("validateEntry() = %T, want *ErrInvalidEntry", err)
+			}
+		})
+	}
+}
+
+func TestNormalizeEntry(t *testing.T) {
+	var nilErr *nilPointerError
+	logged := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		entry     *logrus.Entry
+		wantError string // Expected entry.Data[logrus.ErrorKey] after normalizing, "" for unchanged
+		zeroTime  bool
+	}{
+		{"nil data", &logrus.Entry{Time: logged}, "", false},
+		{"zero time", &logrus.Entry{Data: logrus.Fields{}}, "", true},
+		{"nil pointer error", &logrus.Entry{Time: logged, Data: logrus.Fields{logrus.ErrorKey: nilErr}}, "<nil *discordrus.nilPointerError>", false},
+		{"complete entry", &logrus.Entry{Time: logged, Data: logrus.Fields{"user": "bob"}}, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := tt.entry.Data
+			got := normalizedEntry(tt.entry)
+
+			if got.Data == nil {
+				t.Fatal("normalized entry has nil Data")
+			}
+			if got.Time.IsZero() {
+				t.Error("normalized entry has zero Time")
+			}
+			if !tt.zeroTime && !got.Time.Equal(logged) {
+				t.Errorf("Time = %v, want %v", got.Time, logged)
+			}
+			if tt.wantError != "" && got.Data[logrus.ErrorKey] != tt.wantError {
+				t.Errorf("Data[error] = %#v, want %q", got.Data[logrus.ErrorKey], tt.wantError)
+			}
+			// Entry asli milik pemanggil tidak boleh diubah
+			if before == nil && tt.entry.Data != nil {
+				t.Error("normalizedEntry modified the Data of the original entry")
+			}
+			if tt.wantError != "" && tt.entry.Data[logrus.ErrorKey] != nilErr {
+				t.Error("normalizedEntry modified the error of the original entry")
+			}
+		})
+	}
+}
+
+type stringerValue struct{}
+
+func (stringerValue) String() string { return "stringer" }
+
+func TestFormatFieldValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		value any
+		want  string
+	}{
+		{"nil", nil, "<nil>"},
+		{"string", "plain", "plain"},
+		{"int", 42, "42"},
+		{"float", 1.5, "1.5"},
+		{"bool", true, "true"},
+		{"error", errors.New("boom"), "boom"},
+		{"stringer", stringerValue{}, "stringer"},
+		{"struct", struct{ ID int }{7}, `{"ID":7}`},
+		{"int keys", map[int]string{1: "a"}, `{"1":"a"}`},
+		{"interface keys", map[any]string{1: "a"}, `{"1":"a"}`},
+		{"slice", []string{"a", "b"}, `["a","b"]`},
+		{"unmarshalable", make(chan int), "0x"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatFieldValue(tt.value)
+			if tt.name == "unmarshalable" {
+				if !strings.HasPrefix(got, tt.want) {
+					t.Errorf("formatFieldValue() = %q, want prefix %q", got, tt.want)
+				}
+				return
+			}
+			if got != tt.want {
+				t.Errorf("formatFieldValue() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
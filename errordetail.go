@@ -0,0 +1,49 @@
+package discordrus
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// errorDetail holds the error detail options of a hook
+type errorDetail struct {
+	maxLength int
+}
+
+// WithErrorDetail renders the %+v form of errors implementing fmt.Formatter
+// (e.g. eris or pkg/errors stack traces) in an "ERROR DETAIL" embed.
+// maxLength caps the inline detail (0 uses the embed description limit); longer
+// details are attached in full as error_detail.txt.
+func WithErrorDetail(maxLength int) Option {
+	return func(h *Hook) {
+		h.errorDetail = &errorDetail{maxLength: maxLength}
+	}
+}
+
+// text returns the %+v form of the entry's error, if it adds anything to Error()
+func (d *errorDetail) text(entry *logrus.Entry) (string, bool) {
+	err, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok || err == nil {
+		return "", false
+	}
+	var f fmt.Formatter
+	if !errors.As(err, &f) {
+		return "", false
+	}
+
+	detail := fmt.Sprintf("%+v", err)
+	if detail == "" || detail == err.Error() {
+		return "", false
+	}
+	return detail, true
+}
+
+// limit returns the number of characters of the detail shown inline
+func (d *errorDetail) limit() int {
+	if d.maxLength <= 0 || d.maxLength > maxEmbedDescriptionLength {
+		return maxEmbedDescriptionLength
+	}
+	return d.maxLength
+}
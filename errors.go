@@ -0,0 +1,32 @@
+package discordrus
+
+import (
+	"github.com/murbagus/discordrus/sender"
+	"github.com/rotisserie/eris"
+)
+
+var (
+	// ErrWebhookEmpty is returned when no webhook URL is configured for an entry
+	ErrWebhookEmpty = eris.New("Discord webhook url is empty")
+
+	// ErrHookClosed is returned when an entry is fired after Close
+	ErrHookClosed = eris.New("Discord hook is closed")
+
+	// ErrQueueFull is passed to OnError for asynchronous entries dropped because the
+	// queue was full, see WithWorkerPool
+	ErrQueueFull = eris.New("Discord hook queue is full")
+
+	// ErrPayloadTooLarge is returned when Discord rejects the request body as too large (HTTP 413)
+	// It can be matched with errors.Is on an *ErrDiscordAPI
+	ErrPayloadTooLarge = sender.ErrPayloadTooLarge
+
+	// ErrMessageNotVisible is returned by delivery verification when Discord accepted a
+	// message that cannot be read back completely, see WithDeliveryVerification
+	ErrMessageNotVisible = eris.New("Discord message is not visible after delivery")
+)
+
+// ErrRateLimited is returned when a message is still rate limited after all retries
+type ErrRateLimited = sender.ErrRateLimited
+
+// ErrDiscordAPI is returned when Discord answers with a non-success status code
+type ErrDiscordAPI = sender.ErrDiscordAPI
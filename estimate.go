@@ -0,0 +1,66 @@
+package discordrus
+
+import (
+	"io"
+
+	"github.com/sirupsen/logrus"
+)
+
+// EstimateSize builds the entry like Fire would, without sending it, and returns the characters
+// its embeds count against Discord's limit of 6000 per message and the total attachment size
+// in bytes (each attachment capped at the maximum attachment size). Applications generating
+// bulk alerts can use it to summarize or split entries before firing them.
+//
+// Attachment readers are not consumed: their size is taken from Len, Seek or Open.
+// One-shot readers without any of them can't be measured and count as 0 bytes.
+// It returns 0, 0 when the entry can't be built.
+func (h *Hook) EstimateSize(entry *logrus.Entry) (embedChars int, attachBytes int) {
+	msg, err := h.buildMessage(h.snapshotEntry(entry))
+	if err != nil {
+		return 0, 0
+	}
+	_, embedChars, err = embedStats(msg.Payload)
+	if err != nil {
+		return 0, 0
+	}
+
+	limit := h.attachmentLimit()
+	for _, f := range msg.Files {
+		n := int64(len(f.Data))
+		if f.source != nil {
+			n = f.source.size(limit)
+		}
+		attachBytes += int(min(n, limit))
+	}
+	return embedChars, attachBytes
+}
+
+// size returns the content length of the source without consuming it, up to limit+1 bytes
+func (s *attachmentSource) size(limit int64) int64 {
+	if s.open != nil {
+		r, release, err := s.get()
+		if err != nil {
+			return 0
+		}
+		defer release()
+		n, _ := io.Copy(io.Discard, io.LimitReader(r, limit+1))
+		return n
+	}
+
+	switch r := s.reader.(type) {
+	case interface{ Len() int }:
+		return int64(r.Len())
+	case io.Seeker:
+		cur, err := r.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0
+		}
+		end, err := r.Seek(0, io.SeekEnd)
+		// Kembalikan posisi agar reader tetap bisa dikirim
+		if _, serr := r.Seek(cur, io.SeekStart); err != nil || serr != nil {
+			return 0
+		}
+		return end - cur
+	}
+	return 0
+}
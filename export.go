@@ -0,0 +1,181 @@
+package discordrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"github.com/sirupsen/logrus"
+)
+
+// bundleVersion is the version of the export bundle format
+const bundleVersion = 1
+
+// bundle is the on-disk representation of an exported webhook message
+// It is self-contained: attachments are embedded (base64) next to the payload
+type bundle struct {
+	Version     int                `json:"version"`
+	CreatedAt   time.Time          `json:"created_at"`
+	Payload     json.RawMessage    `json:"payload"`
+	ThreadID    string             `json:"thread_id,omitempty"`
+	Attachments []bundleAttachment `json:"attachments,omitempty"`
+}
+
+type bundleAttachment struct {
+	Name string `json:"name"`
+	Data []byte `json:"data"`
+}
+
+// bundleSeq disambiguates bundles created within the same nanosecond
+var bundleSeq atomic.Uint64
+
+// WithExportDir enables air-gapped export mode: instead of being sent to Discord,
+// every message is written to dir as a self-contained JSON bundle.
+// Exported bundles can later be posted with Replay.
+func WithExportDir(dir string) Option {
+	return func(h *Hook) {
+		h.exportDir = dir
+	}
+}
+
+// exportBundle writes the message into dir as a new bundle file
+// Streamed attachments are read into the bundle, capped at maxAttachmentSize
+func exportBundle(dir string, m *message, maxAttachmentSize int64) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return eris.Wrap(err, "failed to create export directory")
+	}
+
+	now := time.Now().UTC()
+	b := bundle{
+		Version:   bundleVersion,
+		CreatedAt: now,
+		Payload:   json.RawMessage(m.Payload),
+		ThreadID:  m.threadID,
+	}
+	for _, f := range m.Files {
+		data := f.Data
+		if f.source != nil {
+			r, release, err := f.source.get()
+			if err != nil {
+				return eris.Wrapf(err, "failed to open attachment %s", f.Name)
+			}
+			var buf bytes.Buffer
+			err = copyCapped(&buf, r, maxAttachmentSize)
+			release()
+			if err != nil {
+				return eris.Wrapf(err, "failed to read attachment %s", f.Name)
+			}
+			data = buf.Bytes()
+		}
+		b.Attachments = append(b.Attachments, bundleAttachment{Name: f.Name, Data: data})
+	}
+
+	data, err := json.Marshal(b)
+	if err != nil {
+		return eris.Wrap(err, "failed to marshal export bundle")
+	}
+
+	// Tulis ke file sementara lalu rename, agar replayer tidak membaca bundle setengah jadi
+	name := fmt.Sprintf("%s-%06d.json", now.Format("20060102T150405.000000000Z"), bundleSeq.Add(1))
+	tmp := filepath.Join(dir, "."+name+".tmp")
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return eris.Wrap(err, "failed to write export bundle")
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+		os.Remove(tmp)
+		return eris.Wrap(err, "failed to write export bundle")
+	}
+	return nil
+}
+
+// readBundle loads a bundle file back into a message
+func readBundle(path string) (*message, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var b bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, eris.Wrapf(err, "invalid export bundle %s", filepath.Base(path))
+	}
+	if b.Version != bundleVersion {
+		return nil, eris.Errorf("unsupported export bundle version %d in %s", b.Version, filepath.Base(path))
+	}
+
+	m := &message{Payload: b.Payload, threadID: b.ThreadID}
+	for _, a := range b.Attachments {
+		m.Files = append(m.Files, attachment{Name: a.Name, Data: a.Data})
+	}
+	return m, nil
+}
+
+// Replay posts every bundle exported into dir to the given webhook, oldest first.
+// Options are applied to the sending hook, so signing or client certificates can be reused;
+// export mode itself is ignored. Each bundle is removed after it was delivered successfully.
+// Replay stops at the first failure and returns the number of bundles sent so far.
+// The sending hook is closed before Replay returns; when replaying succeeded, the first
+// error of a delivery it flushed on close is returned.
+func Replay(dir string, webhookURL string, opts ...Option) (sent int, err error) {
+	h := NewHook(webhookURL, opts...)
+	var firstErr atomic.Pointer[error]
+	h.OnError = func(err error, _ *logrus.Entry) {
+		firstErr.CompareAndSwap(nil, &err)
+	}
+	// Hook sementara selalu ditutup, termasuk saat replay gagal di tengah jalan
+	defer func() {
+		closeErr := h.Close()
+		if err != nil {
+			return
+		}
+		if p := firstErr.Load(); p != nil {
+			err = eris.Wrap(*p, "failed to deliver replayed message")
+		} else if closeErr != nil {
+			err = closeErr
+		}
+	}()
+	if h.HookUrl == "" {
+		return 0, ErrWebhookEmpty
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, eris.Wrap(err, "failed to read export directory")
+	}
+
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() || strings.HasPrefix(e.Name(), ".") || !strings.HasSuffix(e.Name(), ".json") {
+			continue
+		}
+		names = append(names, e.Name())
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		m, err := readBundle(path)
+		if err != nil {
+			return sent, err
+		}
+		m.URL = h.HookUrl
+		if m.threadID == "" {
+			m.threadID = h.threadID
+		}
+		if _, err := h.send(m); err != nil {
+			return sent, eris.Wrapf(err, "failed to replay %s", name)
+		}
+		if err := os.Remove(path); err != nil {
+			return sent, eris.Wrapf(err, "failed to remove replayed bundle %s", name)
+		}
+		sent++
+	}
+	return sent, nil
+}
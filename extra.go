@@ -0,0 +1,54 @@
+package discordrus
+
+import (
+	"encoding/json"
+)
+
+// webhookPayloadJSON has the fields of WebhookPayload without its JSON methods
+type webhookPayloadJSON WebhookPayload
+
+// MarshalJSON encodes the payload and merges the Extra fields into it
+func (p WebhookPayload) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(webhookPayloadJSON(p))
+	if err != nil || len(p.Extra) == 0 {
+		return data, err
+	}
+
+	var known map[string]json.RawMessage
+	if err := json.Unmarshal(data, &known); err != nil {
+		return nil, err
+	}
+	merged := make(map[string]any, len(p.Extra)+len(known))
+	for k, v := range p.Extra {
+		merged[k] = v
+	}
+	// Field yang sudah diisi menimpa Extra
+	for k, v := range known {
+		merged[k] = v
+	}
+	return json.Marshal(merged)
+}
+
+// UnmarshalJSON decodes the payload and keeps unknown fields in Extra, so
+// payloads survive being rewritten (batching, coalescing, stale summaries)
+func (p *WebhookPayload) UnmarshalJSON(data []byte) error {
+	var known webhookPayloadJSON
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+	for _, k := range []string{"username", "avatar_url", "content", "embeds", "thread_name", "applied_tags"} {
+		delete(all, k)
+	}
+	if len(all) > 0 {
+		known.Extra = make(map[string]any, len(all))
+		for k, v := range all {
+			known.Extra[k] = v
+		}
+	}
+	*p = WebhookPayload(known)
+	return nil
+}
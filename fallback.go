@@ -0,0 +1,56 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"io"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// WithFallbackWriter writes messages that could not be delivered to w, see Hook.FallbackWriter
+//
+//	f, _ := os.OpenFile("discord-fallback.jsonl", os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+//	discordrus.WithFallbackWriter(f)
+func WithFallbackWriter(w io.Writer) Option {
+	return func(h *Hook) {
+		h.FallbackWriter = w
+	}
+}
+
+// fallbackRecord is a message written to the fallback writer
+type fallbackRecord struct {
+	Time       time.Time       `json:"time"`
+	WebhookURL string          `json:"webhook_url"`
+	Error      string          `json:"error"`
+	Payload    json.RawMessage `json:"payload"`
+	Files      []string        `json:"files,omitempty"`
+}
+
+// writeFallback writes the undelivered message to the fallback writer
+func (h *Hook) writeFallback(m *message, deliveryErr error) {
+	record := fallbackRecord{
+		Time:       time.Now().UTC(),
+		WebhookURL: redactWebhookURL(m.URL),
+		Error:      deliveryErr.Error(),
+		Payload:    m.Payload,
+	}
+	if !json.Valid(m.Payload) {
+		record.Payload, _ = json.Marshal(string(m.Payload))
+	}
+	for _, f := range m.Files {
+		record.Files = append(record.Files, f.Name)
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		h.reportError(eris.Wrap(err, "failed to encode fallback record"), m.entry)
+		return
+	}
+
+	// Satu baris per pesan, penulisan diserialisasi agar baris tidak tercampur
+	h.fallbackMu.Lock()
+	defer h.fallbackMu.Unlock()
+	if _, err := h.FallbackWriter.Write(append(line, '\n')); err != nil {
+		h.reportError(eris.Wrap(err, "failed to write to the fallback writer"), m.entry)
+	}
+}
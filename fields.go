@@ -0,0 +1,163 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// maxEmbedFields is Discord's limit of fields in a single embed
+	maxEmbedFields = 25
+
+	// maxFieldValueLength is the number of characters kept from a rendered field value
+	// (Discord allows 1024 per field value, including the code fence)
+	maxFieldValueLength = 1000
+)
+
+// reservedFieldKeys are entry fields that have their own rendering
+var reservedFieldKeys = map[string]bool{
+	REQUEST_FIELD_KEY:       true,
+	RESPONSE_FIELD_KEY:      true,
+	ATTACHMENT_FIELD_KEY:    true,
+	PROGRESS_DONE_FIELD_KEY: true,
+	VALIDATION_FIELD_KEY:    true,
+	TAGS_FIELD_KEY:          true,
+	MUTE_FIELD_KEY:          true,
+	FORCE_SEND_FIELD_KEY:    true,
+	SKIP_FIELD_KEY:          true,
+	systemFieldKey:          true,
+	logrus.ErrorKey:         true,
+}
+
+// WithExcludedFields prevents the given entry fields from being rendered in the embed
+// By default every field of the entry (except the request, error and attachments) is shown.
+func WithExcludedFields(keys ...string) Option {
+	return func(h *Hook) {
+		if h.excludedFields == nil {
+			h.excludedFields = make(map[string]bool, len(keys))
+		}
+		for _, k := range keys {
+			h.excludedFields[k] = true
+		}
+	}
+}
+
+// WithFieldOrder shows the given entry fields first, in this order, after the well-known
+// fields. The remaining fields follow in alphabetical order, e.g.
+//
+//	discordrus.WithFieldOrder("order_id", "customer", "amount")
+func WithFieldOrder(keys ...string) Option {
+	return func(h *Hook) {
+		h.fieldOrder = append([]string(nil), keys...)
+	}
+}
+
+// FieldRendering controls how the value of an entry field is rendered in the embed
+type FieldRendering int
+
+const (
+	// FieldCode renders the value in a code block (the default)
+	FieldCode FieldRendering = iota
+	// FieldPlain renders the value as text with markdown characters escaped
+	FieldPlain
+	// FieldMarkdown renders the value as Discord markdown, e.g. to keep links clickable
+	FieldMarkdown
+)
+
+// markdownEscaper escapes the characters Discord interprets as markdown
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "`", "\\`", "|", `\|`, ">", `\>`, "#", `\#`, "[", `\[`, "]", `\]`,
+)
+
+// WithFieldRendering sets how the given entry fields are rendered, e.g.
+//
+//	discordrus.WithFieldRendering(discordrus.FieldMarkdown, "dashboard", "runbook")
+func WithFieldRendering(r FieldRendering, keys ...string) Option {
+	return func(h *Hook) {
+		if h.fieldRenderings == nil {
+			h.fieldRenderings = make(map[string]FieldRendering, len(keys))
+		}
+		for _, k := range keys {
+			h.fieldRenderings[k] = r
+		}
+	}
+}
+
+// renderFieldValue renders the text of an entry field according to its rendering
+func (h *Hook) renderFieldValue(key, text string) string {
+	rendering := h.fieldRenderings[key]
+	if text == "" && rendering != FieldCode {
+		// Discord menolak field dengan value kosong
+		return "\u200b"
+	}
+	switch rendering {
+	case FieldPlain:
+		return previewText(markdownEscaper.Replace(text), maxFieldValueLength)
+	case FieldMarkdown:
+		return previewText(text, maxFieldValueLength)
+	}
+	return "```" + previewText(text, maxFieldValueLength) + " ```"
+}
+
+// entryFields renders the remaining entry.Data fields as embed fields: the well-known
+// fields first, then the fields of WithFieldOrder, then the others ordered by key
+func (h *Hook) entryFields(entry *logrus.Entry) []EmbedField {
+	fields, used := h.wellKnownEntryFields(entry)
+	shown := func(k string) bool {
+		return !reservedFieldKeys[k] && !h.excludedFields[k] && !used[k] && k != h.traceIDField
+	}
+
+	keys := make([]string, 0, len(entry.Data))
+	pinned := make(map[string]bool, len(h.fieldOrder))
+	for _, k := range h.fieldOrder {
+		if _, ok := entry.Data[k]; ok && shown(k) && !pinned[k] {
+			pinned[k] = true
+			keys = append(keys, k)
+		}
+	}
+	rest := make([]string, 0, len(entry.Data))
+	for k := range entry.Data {
+		if shown(k) && !pinned[k] {
+			rest = append(rest, k)
+		}
+	}
+	sort.Strings(rest)
+	keys = append(keys, rest...)
+
+	for _, k := range keys {
+		if len(fields) == maxEmbedFields {
+			break
+		}
+		fields = append(fields, EmbedField{
+			Name:  k,
+			Value: h.renderFieldValue(k, h.redaction.redactField(k, formatFieldValue(entry.Data[k]))),
+		})
+	}
+	return fields
+}
+
+// formatFieldValue renders an entry field value as text
+func formatFieldValue(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return "<nil>"
+	case string:
+		return val
+	case error:
+		return val.Error()
+	case fmt.Stringer:
+		return val.String()
+	case bool, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64:
+		return fmt.Sprint(val)
+	}
+
+	// Struct, map dan slice ditampilkan sebagai JSON
+	if b, err := json.Marshal(v); err == nil {
+		return string(b)
+	}
+	return fmt.Sprintf("%+v", v)
+}
@@ -0,0 +1,581 @@
+package discordrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WebhookPayload is the message sent to the Discord webhook
+type WebhookPayload struct {
+	Username  string  `json:"username,omitempty"`
+	AvatarURL string  `json:"avatar_url,omitempty"`
+	Content   string  `json:"content,omitempty"`
+	Embeds    []Embed `json:"embeds,omitempty"`
+
+	// ThreadName creates a forum thread with this name for the message, see WithThreadName
+	ThreadName string `json:"thread_name,omitempty"`
+
+	// AppliedTags are the ids of the forum tags applied to the created thread, see WithAppliedTags
+	AppliedTags []string `json:"applied_tags,omitempty"`
+
+	// Files are uploaded together with the payload (multipart/form-data)
+	Files []Attachment `json:"-"`
+
+	// Extra is merged into the outgoing JSON, e.g. "flags" or "applied_tags",
+	// for Discord fields this package does not model yet. Fields set above take precedence.
+	Extra map[string]any `json:"-"`
+}
+
+// Embed is a Discord message embed, see https://discord.com/developers/docs/resources/message#embed-object
+type Embed struct {
+	Title       string       `json:"title,omitempty"`
+	Description string       `json:"description,omitempty"`
+	URL         string       `json:"url,omitempty"`
+	Timestamp   string       `json:"timestamp,omitempty"` // RFC 3339
+	Color       int          `json:"color,omitempty"`
+	Footer      *EmbedFooter `json:"footer,omitempty"`
+	Fields      []EmbedField `json:"fields,omitempty"`
+}
+
+// EmbedField is a name/value field of an embed
+type EmbedField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline,omitempty"`
+}
+
+// EmbedFooter is the footer of an embed
+type EmbedFooter struct {
+	Text    string `json:"text"`
+	IconURL string `json:"icon_url,omitempty"`
+}
+
+// PayloadFormatter builds the Discord payload for a log entry
+// Implement it to fully control the embed layout. Entry attachments passed via
+// ATTACHMENT_FIELD_KEY are added by the hook, as are the username and avatar when left empty.
+type PayloadFormatter interface {
+	Format(entry *logrus.Entry) (*WebhookPayload, error)
+}
+
+// FormatterFunc adapts a function to the PayloadFormatter interface
+type FormatterFunc func(entry *logrus.Entry) (*WebhookPayload, error)
+
+// Format calls f(entry)
+func (f FormatterFunc) Format(entry *logrus.Entry) (*WebhookPayload, error) {
+	return f(entry)
+}
+
+// DefaultFormatter renders entries the way the hook does out of the box:
+// a level embed with the error, a request payload embed and a message embed.
+// The zero value uses the default settings; use Hook.DefaultFormatter to get
+// a formatter bound to a hook's colors and options, e.g. to decorate its output.
+type DefaultFormatter struct {
+	hook *Hook
+}
+
+// WithFormatter replaces the formatter used to build the Discord payload
+func WithFormatter(f PayloadFormatter) Option {
+	return func(h *Hook) {
+		h.payloadFormatter = f
+	}
+}
+
+// DefaultFormatter returns the built-in formatter configured with the hook's settings
+func (h *Hook) DefaultFormatter() *DefaultFormatter {
+	return &DefaultFormatter{hook: h}
+}
+
+// formatter returns the configured formatter or the default one
+func (h *Hook) formatter() PayloadFormatter {
+	if h.payloadFormatter != nil {
+		return h.payloadFormatter
+	}
+	return h.DefaultFormatter()
+}
+
+// Format builds the default payload for the entry
+func (f *DefaultFormatter) Format(entry *logrus.Entry) (*WebhookPayload, error) {
+	h := f.hook
+	if h == nil {
+		h = &Hook{}
+	}
+	if err := validateEntry(entry); err != nil {
+		return nil, err
+	}
+	entry = normalizedEntry(entry)
+	drp := captureRequestPayload(entry, true)
+	labels := h.labelSet()
+
+	errorMessage := ""
+	var errorsFile, bodyFile, curlAttachment, harAttachment *Attachment
+	if v, k := entry.Data["error"]; k {
+		if errVal, ok := v.(error); ok {
+			errorMessage = errVal.Error()
+			// Multi-error yang besar diringkas, daftar lengkapnya dikirim sebagai errors.txt
+			if summary, full, ok := summarizeJoinedErrors(errVal); ok {
+				errorMessage = summary
+				errorsFile = textAttachment("errors.txt", h.redaction.redactText(full))
+			}
+		} else if errVal, ok := v.(string); ok {
+			errorMessage = errVal
+		}
+		errorMessage = h.redaction.redactText(errorMessage)
+	}
+
+	embedCollor := h.colorFor(entry.Level)
+	structured := h.payloadVersion.orDefault() == PayloadV2
+
+	// Request payload fields
+	fields := []EmbedField{}
+
+	// Menambahkan request payload field jika tersedia dalam entry.Data["request"]
+	if drp != nil {
+		var requestBody []byte
+		if drp.Request != nil {
+			// v2: query ditampilkan hanya di field Query, v1 tetap menampilkannya di URL
+			requestURL := h.requestURL(drp.Request)
+			if structured {
+				requestURL = withoutQuery(requestURL)
+			}
+			fields = append(fields,
+				EmbedField{
+					Name:  labels.Method,
+					Value: "```" + drp.Request.Method + " ```",
+				},
+				EmbedField{
+					Name:  labels.URL,
+					Value: "```" + requestURL + " ```",
+				},
+			)
+			if query, ok := queryField(drp.Request.URL, labels.Query); ok && structured {
+				fields = append(fields, query)
+			}
+
+			// Menambahkan mody sesuai dengan content-type
+			var bodyBytes []byte
+			bodyCaptured := drp.Request.Body != http.NoBody
+			if drp.Request.Body != nil && bodyCaptured {
+				bodyBytes, _ = io.ReadAll(drp.Request.Body)
+				requestBody = bodyBytes
+
+				// Kembalikan body ke ReadCloser agar kode berikutnya bisa membacanya
+				drp.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+			}
+
+			contentType := drp.Request.Header.Get("Content-Type")
+			switch {
+			case !bodyCaptured:
+				// Body tidak di-capture (CaptureMetadata)
+
+			case strings.Contains(contentType, "application/json"):
+				if summary, ok := h.summarizeBody(bodyBytes); ok {
+					fields = append(fields, summary)
+					break
+				}
+				field, file := h.bodyField(bodyBytes, contentType, requestBodyFile)
+				fields = append(fields, field)
+				bodyFile = file
+
+			case strings.Contains(contentType, "multipart/form-data"):
+				// Untuk multipart, kita tidak bisa dengan mudah membaca semua bagian file ke string.
+				// Lebih baik parse form-nya dan catat hanya field non-file.
+				// Batas memori untuk parsing form: sesuaikan sesuai kebutuhan
+				const maxMemory = 32 << 20 // 32 MB
+				// Form hanya dibaca jika parsing berhasil, MultipartForm tetap nil untuk
+				// Content-Type yang tidak valid (mis. http.ErrNotMultipart)
+				if err := drp.Request.ParseMultipartForm(maxMemory); err != nil || drp.Request.MultipartForm == nil {
+					if err != nil {
+						fields = append(fields, EmbedField{
+							Name:  labels.Body,
+							Value: "```" + err.Error() + "```",
+						})
+					}
+				} else {
+					formData := make(map[string]any)
+					for key, values := range h.redaction.redactFormValues(drp.Request.MultipartForm.Value) {
+						if len(values) > 1 {
+							formData[key] = values // Bisa jadi slice of strings
+						} else {
+							formData[key] = values[0] // Bisa jadi slice of strings
+						}
+					}
+					// Jangan log FileHeader secara langsung karena berisi metadata file
+					// Anda bisa menambahkan logic untuk mencatat nama file atau ukuran jika diperlukan
+					// Misalnya:
+					fileInfo := make(map[string]any)
+					for key, files := range drp.Request.MultipartForm.File {
+						if len(files) > 1 {
+							var fileNames []string
+							var fileSize []string
+							for _, fileHeader := range files {
+								fileNames = append(fileNames, fileHeader.Filename)
+								fileSize = append(fileSize, fmt.Sprintf("%.2f KB", float64(fileHeader.Size)/1024))
+							}
+
+							fileInfo[key] = map[string]any{
+								labels.FileName: fileNames,
+								labels.FileSize: fileSize,
+							}
+						} else {
+							fileInfo[key] = map[string]any{
+								labels.FileName: files[0].Filename,
+								labels.FileSize: fmt.Sprintf("%.2f KB", float64(files[0].Size)/1024),
+							}
+						}
+					}
+					// 1. Gabungkan formData dan fileInfo ke dalam satu map
+					combinedData := make(map[string]any)
+					if len(formData) > 0 {
+						combinedData[labels.FormFields] = formData
+					}
+					if len(fileInfo) > 0 {
+						combinedData[labels.UploadedFiles] = fileInfo
+					}
+
+					// 2. Ubah combinedData menjadi string JSON
+					jsonString, err := json.MarshalIndent(combinedData, "", "  ") // Gunakan MarshalIndent untuk output yang rapi
+					if err == nil {
+						fields = append(fields, EmbedField{
+							Name:  labels.Body,
+							Value: "```" + string(jsonString) + "```",
+						})
+					}
+				}
+
+			case strings.Contains(contentType, "application/x-www-form-urlencoded"):
+				if len(bodyBytes) > 0 {
+					parsedForm, err := url.ParseQuery(string(bodyBytes))
+					if err != nil {
+						fields = append(fields, EmbedField{
+							Name:  labels.Body,
+							Value: "```" + string(bodyBytes) + " ```",
+						})
+					} else {
+						formData := make(map[string]interface{})
+						for key, values := range parsedForm {
+							formData[key] = values
+						}
+						jsonString, err := json.MarshalIndent(formData, "", "  ") // Gunakan MarshalIndent untuk output yang rapi
+						if err == nil {
+							fields = append(fields, EmbedField{
+								Name:  labels.Body,
+								Value: "```" + string(jsonString) + "```",
+							})
+						}
+					}
+				}
+
+			default:
+				// Untuk Content-Type lain, body teks ditampilkan dan body biner sebagai hex
+				if len(bodyBytes) > 0 {
+					field, file := h.bodyField(bodyBytes, contentType, requestBodyFile)
+					fields = append(fields, field)
+					bodyFile = file
+				}
+			}
+
+			if h.headerRendering != nil {
+				if field, ok := h.headerRendering.field(labels.Headers, drp.Request.Header, h.headerValueLength); ok {
+					fields = append(fields, field)
+				}
+			}
+		} else {
+			if drp.Method != "" {
+				fields = append(fields, EmbedField{
+					Name:  labels.Method,
+					Value: "```" + drp.Method + " ```",
+				})
+			}
+			if drp.URL != "" {
+				requestURL := drp.URL
+				if structured {
+					requestURL = withoutQuery(requestURL)
+				}
+				fields = append(fields, EmbedField{
+					Name:  labels.URL,
+					Value: "```" + requestURL + " ```",
+				})
+				if u, err := url.Parse(drp.URL); err == nil && structured {
+					if query, ok := queryField(u, labels.Query); ok {
+						fields = append(fields, query)
+					}
+				}
+			}
+			if summary, ok := h.summarizeBody([]byte(drp.BodyString)); ok {
+				fields = append(fields, summary)
+			} else if drp.BodyString != "" {
+				field, file := h.bodyField([]byte(drp.BodyString), "", requestBodyFile)
+				fields = append(fields, field)
+				bodyFile = file
+			}
+			if drp.Headers != "" {
+				fields = append(fields, EmbedField{
+					Name:  labels.Headers,
+					Value: "```" + drp.Headers + " ```",
+				})
+			}
+		}
+		fields = append(fields, drp.routeFields(labels)...)
+		fields = append(fields, h.clientFields(drp)...)
+		if field, file, ok := h.curlField(drp, requestBody); ok {
+			fields = append(fields, field)
+			curlAttachment = file
+		}
+		if file, ok := h.harFileFor(entry, drp, requestBody); ok {
+			harAttachment = file
+		}
+	} else if grpcReq := captureGrpcPayload(entry, true); grpcReq != nil {
+		fields = append(fields, h.grpcRequestFields(grpcReq)...)
+	}
+
+	messageToSend := entry.Message
+
+	embeds := []Embed{
+		{
+			Title:       strings.ToUpper(entry.Level.String()),
+			Description: errorMessage,
+			Timestamp:   entry.Time.UTC().Format(time.RFC3339),
+			Color:       embedCollor,
+		},
+	}
+	if !structured {
+		embeds = append(embeds, Embed{
+			Title:  labels.RequestPayload,
+			Fields: fields,
+			Color:  embedCollor,
+		})
+	} else {
+		// v2: error menjadi field pertama, request hanya ditampilkan jika ada
+		embeds[0].Description = ""
+		if errorMessage != "" {
+			embeds[0].Fields = append(embeds[0].Fields, EmbedField{
+				Name:  labels.Error,
+				Value: "```" + previewText(errorMessage, maxFieldValueLength) + " ```",
+			})
+		}
+		if len(fields) > 0 {
+			embeds = append(embeds, Embed{
+				Title:  labels.Request,
+				Fields: fields,
+				Color:  embedCollor,
+			})
+		}
+	}
+
+	var responseBodyFile *Attachment
+	if response, file, ok := h.responseEmbed(entry, embedCollor); ok {
+		embeds = append(embeds, response)
+		responseBodyFile = file
+	}
+	if system, ok := h.systemEmbed(entry, embedCollor); ok {
+		embeds = append(embeds, system)
+	}
+
+	footer := "fingerprint " + h.fingerprint(entry)
+	if identity := h.identity.footer(); identity != "" {
+		footer = identity + " · " + footer
+	}
+	embeds[0].Footer = &EmbedFooter{Text: footer}
+
+	if h.isBackfilled(entry) {
+		markBackfilled(&embeds[0], entry.Time)
+	}
+
+	embeds[0].Fields = append(embeds[0].Fields, h.traceFields(entry)...)
+
+	if caller, ok := h.callerField(entry); ok {
+		embeds[0].Fields = append(embeds[0].Fields, caller)
+	}
+
+	// Field logrus lainnya ditampilkan di embed level
+	embeds[0].Fields = append(embeds[0].Fields, h.entryFields(entry)...)
+
+	if rows := validationRows(entry); len(rows) > 0 {
+		embeds = append(embeds, Embed{
+			Title:       labels.ValidationErrors,
+			Description: "```" + previewText(renderTable(rows), maxEmbedDescriptionLength) + " ```",
+			Color:       embedCollor,
+		})
+	}
+
+	var detailFile *Attachment
+	if h.errorDetail != nil {
+		if detail, ok := h.errorDetail.text(entry); ok {
+			detail = h.redaction.redactText(detail)
+			embed := Embed{
+				Title:       labels.ErrorDetail,
+				Description: "```" + previewText(detail, h.errorDetail.limit()) + " ```",
+				Color:       embedCollor,
+			}
+			// Detail yang terpotong dikirim lengkap sebagai attachment
+			if len([]rune(detail)) > h.errorDetail.limit() {
+				embed.Footer = &EmbedFooter{Text: "Full detail attached as error_detail.txt"}
+				detailFile = &Attachment{Name: "error_detail.txt", Reader: strings.NewReader(detail)}
+			}
+			embeds = append(embeds, embed)
+		}
+	}
+
+	var stackFile *Attachment
+	if h.stackTrace {
+		if frames := errorStack(entry); len(frames) > 0 {
+			embed := Embed{
+				Title:       labels.StackTrace,
+				Description: "```" + previewText(formatStack(frames), maxEmbedDescriptionLength) + " ```",
+				Color:       embedCollor,
+			}
+			// Stack yang panjang dikirim lengkap sebagai attachment
+			if len(frames) > maxInlineStackFrames {
+				embed.Description = "```" + previewText(formatStack(frames[:maxInlineStackFrames]), maxEmbedDescriptionLength) + " ```"
+				embed.Footer = &EmbedFooter{Text: fmt.Sprintf("%d of %d frames shown, full trace attached as stacktrace.txt", maxInlineStackFrames, len(frames))}
+				stackFile = &Attachment{Name: "stacktrace.txt", Reader: strings.NewReader(formatStack(frames))}
+			}
+			embeds = append(embeds, embed)
+		}
+	}
+
+	// Pesan panjang dibagi ke beberapa embed, jika tidak muat dikirim sebagai file attachment (txt)
+	messageEmbeds := splitMessage(messageToSend, labels.Message, embedCollor)
+	if structured && len(messageEmbeds) == 1 {
+		// v2: pesan yang muat dalam satu embed menjadi description embed level
+		embeds[0].Description = messageEmbeds[0].Description
+		messageEmbeds = nil
+	}
+	sendAsFile := len(embeds)+len(messageEmbeds) > maxEmbedsPerMessage ||
+		embedChars(embeds)+embedChars(messageEmbeds) > maxEmbedCharsPerMessage
+	if sendAsFile && structured {
+		embeds[0].Description = ""
+	}
+	if !sendAsFile {
+		embeds = append(embeds, messageEmbeds...)
+	} else {
+		// Tampilkan potongan awal pesan agar bisa dibaca tanpa membuka log.txt
+		embeds = append(embeds, Embed{
+			Title:       labels.MessagePreview,
+			Description: "```" + previewText(messageToSend, maxPreviewLength) + " ```",
+			Footer:      &EmbedFooter{Text: "Full message attached as log.txt"},
+			Color:       embedCollor,
+		})
+	}
+
+	h.applyTemplates(&embeds[0], entry, drp, errorMessage)
+
+	payload := &WebhookPayload{Embeds: embeds}
+	if sendAsFile {
+		payload.Files = append(payload.Files, *textAttachment("log.txt", messageToSend))
+	}
+	if errorsFile != nil {
+		payload.Files = append(payload.Files, *errorsFile)
+	}
+	if bodyFile != nil {
+		payload.Files = append(payload.Files, *bodyFile)
+	}
+	if responseBodyFile != nil {
+		payload.Files = append(payload.Files, *responseBodyFile)
+	}
+	if curlAttachment != nil {
+		payload.Files = append(payload.Files, *curlAttachment)
+	}
+	if harAttachment != nil {
+		payload.Files = append(payload.Files, *harAttachment)
+	}
+	if detailFile != nil {
+		payload.Files = append(payload.Files, *detailFile)
+	}
+	if stackFile != nil {
+		payload.Files = append(payload.Files, *stackFile)
+	}
+	h.applyLayout(payload)
+	return payload, nil
+}
+
+// maxMessageChunkLength is the number of message characters per MESSAGE embed,
+// leaving room for the code fence within Discord's description limit
+const maxMessageChunkLength = maxEmbedDescriptionLength - 8
+
+// splitMessage renders the message as one embed titled title ("MESSAGE"), or several
+// numbered ones ("MESSAGE (2/3)") when it exceeds a single embed description
+func splitMessage(message, title string, color int) []Embed {
+	chunks := splitText(message, maxMessageChunkLength)
+	embeds := make([]Embed, len(chunks))
+	for i, chunk := range chunks {
+		chunkTitle := title
+		if len(chunks) > 1 {
+			chunkTitle = fmt.Sprintf("%s (%d/%d)", title, i+1, len(chunks))
+		}
+		embeds[i] = Embed{
+			Title:       chunkTitle,
+			Description: "```" + chunk + " ```",
+			Color:       color,
+		}
+	}
+	return embeds
+}
+
+// splitText cuts s into chunks of at most n characters (runes), preferably at line breaks
+func splitText(s string, n int) []string {
+	runes := []rune(s)
+	var chunks []string
+	for len(runes) > n {
+		cut := n
+		// Potong di baris baru terakhir jika tidak terlalu jauh dari batas
+		for i := n - 1; i > n/2; i-- {
+			if runes[i] == '\n' {
+				cut = i + 1
+				break
+			}
+		}
+		chunks = append(chunks, string(runes[:cut]))
+		runes = runes[cut:]
+	}
+	return append(chunks, string(runes))
+}
+
+// embedChars returns the characters the embeds count against Discord's per-message limit
+func embedChars(embeds []Embed) int {
+	chars := 0
+	for _, e := range embeds {
+		chars += len([]rune(e.Title)) + len([]rune(e.Description))
+		if e.Footer != nil {
+			chars += len([]rune(e.Footer.Text))
+		}
+		for _, f := range e.Fields {
+			chars += len([]rune(f.Name)) + len([]rune(f.Value))
+		}
+	}
+	return chars
+}
+
+// routeFields renders the client address and path parameters of the request, if set
+func (p *LoggerHttpRequestPayload) routeFields(labels *Labels) []EmbedField {
+	var fields []EmbedField
+	if p.ClientIP != "" {
+		fields = append(fields, EmbedField{Name: labels.ClientIP, Value: "```" + p.ClientIP + " ```", Inline: true})
+	}
+	if len(p.PathParams) > 0 {
+		names := make([]string, 0, len(p.PathParams))
+		for name := range p.PathParams {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		lines := make([]string, 0, len(names))
+		for _, name := range names {
+			lines = append(lines, name+": "+p.PathParams[name])
+		}
+		fields = append(fields, EmbedField{
+			Name:  labels.PathParams,
+			Value: "```" + previewText(strings.Join(lines, "\n"), maxFieldValueLength) + " ```",
+		})
+	}
+	return fields
+}
@@ -0,0 +1,118 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestFormatMultipartWithoutForm(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+	}{
+		{"not multipart", "text/plain; note=multipart/form-data"},
+		{"missing boundary", "multipart/form-data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/upload", strings.NewReader("name=x"))
+			req.Header.Set("Content-Type", tt.contentType)
+			entry := &logrus.Entry{
+				Logger:  logrus.New(),
+				Level:   logrus.ErrorLevel,
+				Message: "upload failed",
+				Data:    logrus.Fields{REQUEST_FIELD_KEY: LoggerHttpRequestPayload{Request: req}},
+			}
+
+			h := NewHook("https://discord.com/api/webhooks/1/token")
+			if _, err := h.DefaultFormatter().Format(entry); err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestFormatQueryFieldByPayloadVersion(t *testing.T) {
+	tests := []struct {
+		version   PayloadVersion
+		wantQuery bool
+	}{
+		{PayloadV1, false},
+		{PayloadV2, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version.String(), func(t *testing.T) {
+			req := httptest.NewRequest("GET", "/orders?page=2", nil)
+			entry := &logrus.Entry{
+				Logger:  logrus.New(),
+				Level:   logrus.ErrorLevel,
+				Message: "lookup failed",
+				Data:    logrus.Fields{REQUEST_FIELD_KEY: LoggerHttpRequestPayload{Request: req}},
+			}
+
+			h := NewHook("https://discord.com/api/webhooks/1/token", WithPayloadVersion(tt.version))
+			payload, err := h.DefaultFormatter().Format(entry)
+			if err != nil {
+				t.Fatalf("Format() error = %v", err)
+			}
+			var query, urls int
+			for _, e := range payload.Embeds {
+				for _, f := range e.Fields {
+					switch f.Name {
+					case "Query":
+						query++
+					case "URL":
+						if strings.Contains(f.Value, "page=2") {
+							urls++
+						}
+					}
+				}
+			}
+			if (query == 1) != tt.wantQuery || query > 1 {
+				t.Errorf("got %d Query fields, want query field %v", query, tt.wantQuery)
+			}
+			// Query hanya boleh tampil sekali: di URL (v1) atau di field Query (v2)
+			if query+urls != 1 {
+				t.Errorf("query shown %d times, want once", query+urls)
+			}
+		})
+	}
+}
+
+func TestFormatRedactsEntryFields(t *testing.T) {
+	type user struct {
+		Name     string `json:"name"`
+		Password string `json:"password"`
+	}
+	entry := &logrus.Entry{
+		Logger:  logrus.New(),
+		Level:   logrus.ErrorLevel,
+		Message: "login failed",
+		Data: logrus.Fields{
+			"token":  "tok-123",
+			"user":   user{Name: "bob", Password: "hunter2"},
+			"nested": map[string]any{"items": []any{map[string]any{"Token": "tok-456"}}},
+			"card":   "card 4111111111111111",
+		},
+	}
+
+	h := NewHook("https://discord.com/api/webhooks/1/token", WithRedactionPatterns(regexp.MustCompile(`\b\d{16}\b`)))
+	payload, err := h.DefaultFormatter().Format(entry)
+	if err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+	data, _ := json.Marshal(payload)
+	for _, secret := range []string{"tok-123", "hunter2", "tok-456", "4111111111111111"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("payload contains %q", secret)
+		}
+	}
+	if !strings.Contains(string(data), "bob") {
+		t.Error("payload lost the non-secret user name")
+	}
+}
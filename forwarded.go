@@ -0,0 +1,149 @@
+package discordrus
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// WithTrustedProxies renders request URLs as the client called them: scheme and host are
+// taken from the Forwarded header (RFC 7239) or X-Forwarded-Proto and X-Forwarded-Host when
+// the request comes from one of the proxies, otherwise from the request itself, e.g.
+//
+//	discordrus.WithTrustedProxies(netip.MustParsePrefix("10.0.0.0/8"))
+//
+// Without trusted proxies, the URL is rendered as received, usually just the path.
+func WithTrustedProxies(proxies ...netip.Prefix) Option {
+	return func(h *Hook) {
+		h.trustedProxies = append(h.trustedProxies, proxies...)
+	}
+}
+
+// requestURL renders the URL of a logged request, see WithTrustedProxies
+func (h *Hook) requestURL(r *http.Request) string {
+	if len(h.trustedProxies) == 0 || r.URL.IsAbs() {
+		return r.URL.String()
+	}
+
+	scheme, host := "http", r.Host
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if h.trustedProxy(r.RemoteAddr) {
+		proto, forwardedHost := forwardedFor(r.Header)
+		if proto != "" {
+			scheme = proto
+		}
+		if forwardedHost != "" {
+			host = forwardedHost
+		}
+	}
+	if host == "" {
+		return r.URL.String()
+	}
+	return scheme + "://" + host + r.URL.RequestURI()
+}
+
+// trustedProxy reports whether the remote address belongs to a trusted proxy
+func (h *Hook) trustedProxy(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range h.trustedProxies {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedFor returns the scheme and host the client called, as reported by the proxy
+// The first proxy of a chain is the one the client connected to.
+func forwardedFor(header http.Header) (proto, host string) {
+	if forwarded := header.Get("Forwarded"); forwarded != "" {
+		first, _, _ := strings.Cut(forwarded, ",")
+		for _, pair := range strings.Split(first, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				continue
+			}
+			value = strings.Trim(value, `"`)
+			switch strings.ToLower(name) {
+			case "proto":
+				proto = strings.ToLower(value)
+			case "host":
+				host = value
+			}
+		}
+		if proto != "" || host != "" {
+			return proto, host
+		}
+	}
+
+	proto, _, _ = strings.Cut(header.Get("X-Forwarded-Proto"), ",")
+	host, _, _ = strings.Cut(header.Get("X-Forwarded-Host"), ",")
+	return strings.ToLower(strings.TrimSpace(proto)), strings.TrimSpace(host)
+}
+
+// clientIP returns the address of the client that sent the request. Behind trusted
+// proxies it is the last address of the Forwarded (for=) or X-Forwarded-For chain that
+// is not a trusted proxy itself, since the entries before it can be forged by the client.
+func (h *Hook) clientIP(r *http.Request) string {
+	remote, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		remote = r.RemoteAddr
+	}
+	if !h.trustedProxy(remote) {
+		return remote
+	}
+
+	chain := forwardedChain(r.Header)
+	for i := len(chain) - 1; i >= 0; i-- {
+		if !h.trustedProxy(chain[i]) {
+			return chain[i]
+		}
+	}
+	if len(chain) > 0 {
+		return chain[0]
+	}
+	return remote
+}
+
+// forwardedChain returns the client addresses reported by the proxies, client first
+func forwardedChain(header http.Header) []string {
+	var chain []string
+	for _, element := range strings.Split(strings.Join(header.Values("Forwarded"), ","), ",") {
+		for _, pair := range strings.Split(element, ";") {
+			name, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if ok && strings.EqualFold(name, "for") {
+				chain = append(chain, forwardedAddr(value))
+			}
+		}
+	}
+	if len(chain) > 0 {
+		return chain
+	}
+	for _, addr := range strings.Split(strings.Join(header.Values("X-Forwarded-For"), ","), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			chain = append(chain, forwardedAddr(addr))
+		}
+	}
+	return chain
+}
+
+// forwardedAddr strips quotes, brackets and the port from a forwarded address,
+// e.g. `"[2001:db8::1]:4711"` or "192.0.2.60:8080"
+func forwardedAddr(v string) string {
+	v = strings.Trim(strings.TrimSpace(v), `"`)
+	if host, _, err := net.SplitHostPort(v); err == nil {
+		return host
+	}
+	return strings.Trim(v, "[]")
+}
@@ -0,0 +1,62 @@
+// Package ginhook provides a Gin recovery middleware that reports panics through a logger
+// with the discordrus hook, including the request, its path parameters and the client IP.
+// Entries logged with the request context carry the request as well.
+//
+// Usage:
+//
+//	router := gin.New()
+//	router.Use(ginhook.Recovery(logger))
+//
+//	router.POST("/orders/:id", func(c *gin.Context) {
+//		logger.WithContext(c.Request.Context()).WithError(err).Error("failed to update order")
+//	})
+package ginhook
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/murbagus/discordrus"
+	"github.com/sirupsen/logrus"
+)
+
+// Recovery stores the request in the request context and recovers panics of later
+// handlers: they are logged at error level and the request is aborted with status 500
+func Recovery(logger *logrus.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = discordrus.CaptureRequest(c.Request)
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			// Koneksi sengaja diputus oleh handler, biarkan server yang menanganinya
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			logger.WithFields(logrus.Fields{
+				logrus.ErrorKey:              discordrus.PanicError(v),
+				discordrus.REQUEST_FIELD_KEY: Payload(c),
+			}).Errorf("panic serving %s %s", c.Request.Method, c.FullPath())
+			c.AbortWithStatus(http.StatusInternalServerError)
+		}()
+		c.Next()
+	}
+}
+
+// Payload returns the request payload of c with its path parameters and client IP
+func Payload(c *gin.Context) discordrus.LoggerHttpRequestPayload {
+	p, ok := discordrus.RequestFromContext(c.Request.Context())
+	if !ok {
+		p = discordrus.LoggerHttpRequestPayload{Request: c.Request}
+	}
+	p.ClientIP = c.ClientIP()
+	if len(c.Params) > 0 {
+		p.PathParams = make(map[string]string, len(c.Params))
+		for _, param := range c.Params {
+			p.PathParams[param.Key] = param.Value
+		}
+	}
+	return p
+}
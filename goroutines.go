@@ -0,0 +1,131 @@
+package discordrus
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+var (
+	// goroutineHeader matches "goroutine 12 [chan receive, 3 minutes]:"
+	goroutineHeader = regexp.MustCompile(`^goroutine \d+ \[([^,\]]+)[^\]]*\]:$`)
+	// goroutineArgs matches the argument list of a stack frame, e.g. "(0xc000010000, 0x3)"
+	goroutineArgs = regexp.MustCompile(`\([^()]*\)$`)
+	// goroutineCreator matches the creating goroutine id of "created by" lines
+	goroutineCreator = regexp.MustCompile(` in goroutine \d+$`)
+)
+
+// GoroutineDump returns the stacks of all goroutines as a "goroutines.txt" attachment
+// Goroutines with identical stacks are collapsed into one block ("x42 goroutines [...]"),
+// which keeps dumps of large worker pools small:
+//
+//	logger.WithField(discordrus.ATTACHMENT_FIELD_KEY, discordrus.GoroutineDump()).Error("deadlock suspected")
+func GoroutineDump() Attachment {
+	buf := make([]byte, 64<<10)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			buf = buf[:n]
+			break
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+	return Attachment{Name: "goroutines.txt", Reader: strings.NewReader(collapseStacks(string(buf)))}
+}
+
+// WithGoroutineDump attaches the stacks of all goroutines, see GoroutineDump, to panic
+// and fatal entries. The dump is taken when the entry fires, which helps diagnosing
+// deadlocks and crashes after the process is gone.
+func WithGoroutineDump() Option {
+	return func(h *Hook) {
+		h.goroutineDump = true
+	}
+}
+
+// addGoroutineDump adds a goroutine dump to the attachments of panic and fatal entries
+func (h *Hook) addGoroutineDump(entry *logrus.Entry) {
+	if !h.goroutineDump || entry.Level > logrus.FatalLevel {
+		return
+	}
+	var list []Attachment
+	switch v := entry.Data[ATTACHMENT_FIELD_KEY].(type) {
+	case Attachment:
+		list = []Attachment{v}
+	case *Attachment:
+		if v != nil {
+			list = []Attachment{*v}
+		}
+	case []Attachment:
+		list = append(list, v...)
+	}
+	entry.Data[ATTACHMENT_FIELD_KEY] = append(list, GoroutineDump())
+}
+
+// stackGroup is a set of goroutines sharing the same state and stack
+type stackGroup struct {
+	header string // Header of the first goroutine
+	state  string
+	stack  string
+	count  int
+}
+
+// collapseStacks collapses identical goroutine stacks of a runtime.Stack dump
+// Stacks are compared without frame arguments and goroutine ids; groups keep
+// the order of their first goroutine.
+func collapseStacks(dump string) string {
+	var groups []*stackGroup
+	index := make(map[string]*stackGroup)
+
+	for _, block := range strings.Split(strings.TrimSpace(dump), "\n\n") {
+		header, stack, _ := strings.Cut(block, "\n")
+		m := goroutineHeader.FindStringSubmatch(header)
+		if m == nil {
+			// Blok yang tidak dikenali dibiarkan apa adanya
+			groups = append(groups, &stackGroup{header: block, count: 1})
+			continue
+		}
+
+		key := m[1] + "\n" + normalizeStack(stack)
+		if g, ok := index[key]; ok {
+			g.count++
+			continue
+		}
+		g := &stackGroup{header: header, state: m[1], stack: stack, count: 1}
+		index[key] = g
+		groups = append(groups, g)
+	}
+
+	var out bytes.Buffer
+	for i, g := range groups {
+		if i > 0 {
+			out.WriteString("\n\n")
+		}
+		if g.count > 1 {
+			fmt.Fprintf(&out, "x%d goroutines [%s]:", g.count, g.state)
+		} else {
+			out.WriteString(g.header)
+		}
+		if g.stack != "" {
+			out.WriteString("\n" + g.stack)
+		}
+	}
+	out.WriteString("\n")
+	return out.String()
+}
+
+// normalizeStack removes the parts of a stack that differ between otherwise identical goroutines
+func normalizeStack(stack string) string {
+	lines := strings.Split(stack, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "\t") {
+			continue
+		}
+		line = goroutineArgs.ReplaceAllString(line, "(...)")
+		lines[i] = goroutineCreator.ReplaceAllString(line, "")
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,136 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// LoggerGrpcRequestPayload holds gRPC request information for logging.
+// Pass it in entry.Data[REQUEST_FIELD_KEY], like LoggerHttpRequestPayload; the grpchook
+// package fills it from interceptors.
+type LoggerGrpcRequestPayload struct {
+	FullMethod string              // Full RPC method name, e.g. "/billing.v1.Payments/Charge"
+	Metadata   map[string][]string // Incoming metadata, a metadata.MD can be assigned directly
+	Message    any                 // Request message, rendered as JSON (a json.RawMessage is used as is)
+	Code       string              // Optional status code of the failed call, e.g. "Unavailable"
+	Peer       string              // Optional address of the client
+}
+
+// capturedGrpcRequest is the copy of a gRPC request payload taken when the entry is fired
+type capturedGrpcRequest struct {
+	FullMethod      string
+	Metadata        map[string][]string
+	Message         string // JSON rendering of the request message
+	MessageCaptured bool
+	Code            string
+	Peer            string
+}
+
+// captureGrpcPayload makes a copy of the gRPC request payload in entry.Data[REQUEST_FIELD_KEY],
+// rendering the message right away since it may be reused after the call. It returns nil
+// if there is none. The message is only rendered when withMessage is true.
+func captureGrpcPayload(entry *logrus.Entry, withMessage bool) *capturedGrpcRequest {
+	var p LoggerGrpcRequestPayload
+	switch v := entry.Data[REQUEST_FIELD_KEY].(type) {
+	case LoggerGrpcRequestPayload:
+		p = v
+	case *LoggerGrpcRequestPayload:
+		if v == nil {
+			return nil
+		}
+		p = *v
+	case capturedGrpcRequest:
+		return &v
+	default:
+		return nil
+	}
+
+	c := &capturedGrpcRequest{
+		FullMethod:      p.FullMethod,
+		Metadata:        make(map[string][]string, len(p.Metadata)),
+		MessageCaptured: withMessage,
+		Code:            p.Code,
+		Peer:            p.Peer,
+	}
+	for k, v := range p.Metadata {
+		c.Metadata[k] = append([]string(nil), v...)
+	}
+	if withMessage && p.Message != nil {
+		if b, err := json.Marshal(p.Message); err == nil {
+			c.Message = string(b)
+		} else {
+			c.Message = formatFieldValue(p.Message)
+		}
+	}
+	return c
+}
+
+// redactGrpcRequest redacts a captured gRPC request in place
+// Header rules apply to metadata keys, which gRPC sends in lower case.
+func (r *RedactionRules) redactGrpcRequest(c *capturedGrpcRequest) {
+	if r.empty() {
+		return
+	}
+	for key, values := range c.Metadata {
+		for _, name := range r.Headers {
+			if strings.EqualFold(key, name) {
+				for i := range values {
+					values[i] = redactedValue
+				}
+			}
+		}
+		for i := range values {
+			values[i] = r.redactText(values[i])
+		}
+	}
+	c.Message = r.redactText(string(r.redactJSON([]byte(c.Message))))
+}
+
+// grpcRequestFields renders a captured gRPC request as the fields of the request embed
+func (h *Hook) grpcRequestFields(c *capturedGrpcRequest) []EmbedField {
+	labels := h.labelSet()
+	fields := []EmbedField{{
+		Name:  labels.Method,
+		Value: "```" + c.FullMethod + " ```",
+	}}
+	if c.Code != "" {
+		fields = append(fields, EmbedField{Name: labels.Code, Value: "```" + c.Code + " ```", Inline: true})
+	}
+	if c.Peer != "" {
+		fields = append(fields, EmbedField{Name: labels.Peer, Value: "```" + c.Peer + " ```", Inline: true})
+	}
+	if c.MessageCaptured && c.Message != "" && c.Message != "null" {
+		if summary, ok := h.summarizeBody([]byte(c.Message)); ok {
+			fields = append(fields, summary)
+		} else {
+			fields = append(fields, EmbedField{
+				Name:  labels.GrpcMessage,
+				Value: "```" + previewText(c.Message, maxFieldValueLength) + " ```",
+			})
+		}
+	}
+	if len(c.Metadata) > 0 {
+		keys := make([]string, 0, len(c.Metadata))
+		for k := range c.Metadata {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		valueLength := h.headerValueLength
+		if valueLength <= 0 {
+			valueLength = defaultHeaderValueLength
+		}
+		lines := make([]string, 0, len(keys))
+		for _, k := range keys {
+			lines = append(lines, k+": "+middleEllipsis(strings.Join(c.Metadata[k], ", "), valueLength))
+		}
+		fields = append(fields, EmbedField{
+			Name:  labels.Metadata,
+			Value: "```" + previewText(strings.Join(lines, "\n"), maxFieldValueLength) + " ```",
+		})
+	}
+	return fields
+}
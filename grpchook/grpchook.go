@@ -0,0 +1,124 @@
+// Package grpchook provides gRPC server interceptors that report failing RPCs through a
+// logger with the discordrus hook, including the method, metadata and request message.
+//
+// Usage:
+//
+//	server := grpc.NewServer(
+//		grpc.ChainUnaryInterceptor(grpchook.UnaryServerInterceptor(logger)),
+//		grpc.ChainStreamInterceptor(grpchook.StreamServerInterceptor(logger)),
+//	)
+package grpchook
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/murbagus/discordrus"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultCodes are the status codes reported when no codes are configured: the ones
+// that point to a problem of the server rather than of the client's request
+var DefaultCodes = []codes.Code{
+	codes.Unknown, codes.DeadlineExceeded, codes.Unimplemented,
+	codes.Internal, codes.Unavailable, codes.DataLoss,
+}
+
+// Option configures the interceptors
+type Option func(*config)
+
+type config struct {
+	codes map[codes.Code]bool
+	level logrus.Level
+}
+
+// WithCodes reports failed calls with the given status codes instead of DefaultCodes
+func WithCodes(cs ...codes.Code) Option {
+	return func(c *config) {
+		c.codes = make(map[codes.Code]bool, len(cs))
+		for _, code := range cs {
+			c.codes[code] = true
+		}
+	}
+}
+
+// WithLevel sets the level failed calls are logged at (default Error)
+func WithLevel(level logrus.Level) Option {
+	return func(c *config) {
+		c.level = level
+	}
+}
+
+func newConfig(opts []Option) *config {
+	c := &config{level: logrus.ErrorLevel}
+	WithCodes(DefaultCodes...)(c)
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// UnaryServerInterceptor logs unary calls failing with one of the configured codes
+func UnaryServerInterceptor(logger *logrus.Logger, opts ...Option) grpc.UnaryServerInterceptor {
+	c := newConfig(opts)
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		resp, err := handler(ctx, req)
+		c.report(logger, ctx, info.FullMethod, req, err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor logs streaming calls failing with one of the configured codes
+// The stream's messages are not included.
+func StreamServerInterceptor(logger *logrus.Logger, opts ...Option) grpc.StreamServerInterceptor {
+	c := newConfig(opts)
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		err := handler(srv, ss)
+		c.report(logger, ss.Context(), info.FullMethod, nil, err)
+		return err
+	}
+}
+
+// report logs the failed call if its code is configured
+func (c *config) report(logger *logrus.Logger, ctx context.Context, fullMethod string, req any, err error) {
+	if err == nil {
+		return
+	}
+	code := status.Code(err)
+	if !c.codes[code] {
+		return
+	}
+	logger.WithContext(ctx).WithFields(logrus.Fields{
+		discordrus.REQUEST_FIELD_KEY: Payload(ctx, fullMethod, req, err),
+		logrus.ErrorKey:              err,
+	}).Logf(c.level, "gRPC %s failed: %s", fullMethod, code)
+}
+
+// Payload builds the request payload of a call, e.g. for logging from a custom interceptor
+// Proto messages are rendered with protojson, err may be nil.
+func Payload(ctx context.Context, fullMethod string, req any, err error) discordrus.LoggerGrpcRequestPayload {
+	p := discordrus.LoggerGrpcRequestPayload{FullMethod: fullMethod, Message: req}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		p.Metadata = md
+	}
+	if pr, ok := peer.FromContext(ctx); ok && pr.Addr != nil {
+		p.Peer = pr.Addr.String()
+	}
+	if err != nil {
+		p.Code = status.Code(err).String()
+	}
+	if msg, ok := req.(proto.Message); ok {
+		// protojson memakai nama field proto, bukan nama field Go
+		if b, err := protojson.Marshal(msg); err == nil {
+			p.Message = json.RawMessage(b)
+		}
+	}
+	return p
+}
@@ -0,0 +1,196 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"runtime/debug"
+	"sort"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// harFile is the name the HAR export is attached as
+const harFile = "request.har"
+
+// WithHARAttachment attaches the logged HTTP request, and its response when the entry
+// carries one, as a HAR 1.2 file (request.har) that can be imported into browser devtools,
+// Postman or Insomnia. The export is built after redaction.
+func WithHARAttachment() Option {
+	return func(h *Hook) {
+		h.harAttachment = true
+	}
+}
+
+// harLog is the root of a HAR document, see http://www.softwareishard.com/blog/har-12-spec/
+type harLog struct {
+	Log struct {
+		Version string     `json:"version"`
+		Creator harCreator `json:"creator"`
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// harFileFor builds the HAR export of the entry's request, see WithHARAttachment;
+// body is the captured request body
+func (h *Hook) harFileFor(entry *logrus.Entry, p *LoggerHttpRequestPayload, body []byte) (*Attachment, bool) {
+	if !h.harAttachment {
+		return nil, false
+	}
+
+	req := harRequest{Cookies: []harNameValue{}}
+	var header http.Header
+	if p.Request != nil {
+		req.Method, req.URL, req.HTTPVersion = p.Request.Method, h.absoluteRequestURL(p.Request), p.Request.Proto
+		header = p.Request.Header
+		for _, c := range p.Request.Cookies() {
+			req.Cookies = append(req.Cookies, harNameValue{Name: c.Name, Value: c.Value})
+		}
+	} else {
+		req.Method, req.URL, body = p.Method, p.URL, []byte(p.BodyString)
+		header = parseHeaderLines(p.Headers)
+	}
+	if req.URL == "" {
+		return nil, false
+	}
+	if req.HTTPVersion == "" {
+		req.HTTPVersion = "HTTP/1.1"
+	}
+	req.Headers = harHeaders(header)
+	if u, err := url.Parse(req.URL); err == nil {
+		req.QueryString = harValues(u.Query())
+	}
+	req.HeadersSize, req.BodySize = -1, len(body)
+	if len(body) > 0 && !isBinary(body) {
+		req.PostData = &harPostData{MimeType: header.Get("Content-Type"), Text: string(body)}
+	}
+
+	// Response wajib ada di HAR, status 0 menandakan tidak ada response
+	resp := harResponse{HTTPVersion: req.HTTPVersion, Cookies: []harNameValue{}, Headers: []harNameValue{}, HeadersSize: -1, BodySize: -1}
+	var latency time.Duration
+	if c := captureResponsePayload(entry, true); c != nil {
+		latency = c.Latency
+		responseHeader := c.Header
+		if responseHeader == nil {
+			responseHeader = parseHeaderLines(c.Headers)
+		}
+		resp.Status, resp.StatusText = c.StatusCode, http.StatusText(c.StatusCode)
+		resp.Headers = harHeaders(responseHeader)
+		resp.Content = harContent{Size: len(c.Body), MimeType: responseHeader.Get("Content-Type")}
+		if !isBinary([]byte(c.Body)) {
+			resp.Content.Text = c.Body
+		}
+		resp.BodySize = len(c.Body)
+	}
+
+	ms := float64(latency) / float64(time.Millisecond)
+	var doc harLog
+	doc.Log.Version = "1.2"
+	doc.Log.Creator = harCreator{Name: "discordrus", Version: moduleVersion()}
+	doc.Log.Entries = []harEntry{{
+		StartedDateTime: entry.Time.Add(-latency).UTC().Format(time.RFC3339Nano),
+		Time:            ms,
+		Request:         req,
+		Response:        resp,
+		Timings:         harTimings{Wait: ms},
+	}}
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, false
+	}
+	return textAttachment(harFile, string(data)), true
+}
+
+// harHeaders converts headers to HAR name/value pairs, sorted by name
+func harHeaders(header http.Header) []harNameValue {
+	return harValues(url.Values(header))
+}
+
+// harValues converts multi-valued pairs to HAR name/value pairs, sorted by name
+func harValues(values url.Values) []harNameValue {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	pairs := []harNameValue{}
+	for _, name := range names {
+		for _, v := range values[name] {
+			pairs = append(pairs, harNameValue{Name: name, Value: v})
+		}
+	}
+	return pairs
+}
+
+// moduleVersion returns the version of this package in the running binary, "devel" when unknown
+func moduleVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, dep := range info.Deps {
+			if dep.Path == "github.com/murbagus/discordrus" {
+				return dep.Version
+			}
+		}
+	}
+	return "devel"
+}
@@ -0,0 +1,107 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// HeaderFormat controls how request headers are rendered
+type HeaderFormat int
+
+const (
+	// HeaderFormatLines renders one "Name: value" line per header
+	HeaderFormatLines HeaderFormat = iota
+	// HeaderFormatJSON renders the headers as an indented JSON object
+	HeaderFormatJSON
+)
+
+// defaultHeaderValueLength is the number of characters kept from a single header value
+const defaultHeaderValueLength = 200
+
+// headerRendering holds the header options of a hook
+type headerRendering struct {
+	format    HeaderFormat
+	maxLength int
+}
+
+// WithRequestHeaders includes the headers of *http.Request payloads as a "Headers" field.
+// maxLength caps the rendered value (0 uses the default field limit).
+func WithRequestHeaders(format HeaderFormat, maxLength int) Option {
+	return func(h *Hook) {
+		h.headerRendering = &headerRendering{format: format, maxLength: maxLength}
+	}
+}
+
+// WithRequestHeaderValueLength caps every single header value at n characters (default 200),
+// so one oversized header (e.g. Cookie or a forwarded chain) doesn't take the whole field.
+// Longer values keep their start and end around a "…[N chars omitted]…" marker.
+// It only has an effect together with WithRequestHeaders.
+func WithRequestHeaderValueLength(n int) Option {
+	return func(h *Hook) {
+		h.headerValueLength = n
+	}
+}
+
+// middleEllipsis shortens s to about n characters (runes) by cutting out its middle
+func middleEllipsis(s string, n int) string {
+	runes := []rune(s)
+	if n <= 0 || len(runes) <= n {
+		return s
+	}
+	head := (n + 1) / 2
+	tail := n - head
+	return fmt.Sprintf("%s…[%d chars omitted]…%s", string(runes[:head]), len(runes)-n, string(runes[len(runes)-tail:]))
+}
+
+// field renders the headers as an embed field titled name, sorted by name
+// Every header value is cut to valueLength characters (0 uses the default).
+func (r *headerRendering) field(name string, header http.Header, valueLength int) (EmbedField, bool) {
+	if len(header) == 0 {
+		return EmbedField{}, false
+	}
+
+	if valueLength <= 0 {
+		valueLength = defaultHeaderValueLength
+	}
+	value := func(name string) string {
+		return middleEllipsis(strings.Join(header.Values(name), ", "), valueLength)
+	}
+
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var rendered string
+	switch r.format {
+	case HeaderFormatJSON:
+		obj := make(map[string]string, len(names))
+		for _, name := range names {
+			obj[name] = value(name)
+		}
+		b, err := json.MarshalIndent(obj, "", "  ")
+		if err != nil {
+			return EmbedField{}, false
+		}
+		rendered = string(b)
+	default:
+		lines := make([]string, 0, len(names))
+		for _, name := range names {
+			lines = append(lines, name+": "+value(name))
+		}
+		rendered = strings.Join(lines, "\n")
+	}
+
+	maxLength := r.maxLength
+	if maxLength <= 0 || maxLength > maxFieldValueLength {
+		maxLength = maxFieldValueLength
+	}
+	return EmbedField{
+		Name:  name,
+		Value: "```" + previewText(rendered, maxLength) + " ```",
+	}, true
+}
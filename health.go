@@ -0,0 +1,106 @@
+package discordrus
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/murbagus/discordrus/sender"
+	"github.com/rotisserie/eris"
+)
+
+// healthProbe periodically checks that the hook's webhooks still exist
+type healthProbe struct {
+	interval time.Duration
+	onChange func(stats DestinationStats)
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+// WithHealthProbe checks every interval whether the webhooks of the hook are still reachable,
+// by fetching the webhook object from Discord. Broken webhooks (deleted, invalid token,
+// network failures) are reported as StateUnhealthy in Stats before the next real error needs
+// them. onChange is optional and called whenever a webhook turns unhealthy or healthy again.
+// The probe stops when the hook is closed.
+func WithHealthProbe(interval time.Duration, onChange func(stats DestinationStats)) Option {
+	return func(h *Hook) {
+		if interval > 0 {
+			h.probe = &healthProbe{interval: interval, onChange: onChange, stop: make(chan struct{})}
+		}
+	}
+}
+
+// startProbe runs the health probe in the background until the hook is closed
+func (h *Hook) startProbe() {
+	go func() {
+		ticker := time.NewTicker(h.probe.interval)
+		defer ticker.Stop()
+		for {
+			h.probeAll()
+			select {
+			case <-ticker.C:
+			case <-h.probe.stop:
+				return
+			}
+		}
+	}()
+}
+
+// stopProbe stops the health probe, if it is running
+func (h *Hook) stopProbe() {
+	if h.probe != nil {
+		h.probe.stopOnce.Do(func() { close(h.probe.stop) })
+	}
+}
+
+// probeAll probes the default webhook and every category and level route
+func (h *Hook) probeAll() {
+	seen := make(map[string]bool)
+	urls := []string{h.HookUrl}
+	for _, webhookURL := range h.categoryRoutes {
+		urls = append(urls, webhookURL)
+	}
+	for _, webhookURL := range h.levelRoutes {
+		urls = append(urls, webhookURL)
+	}
+	for _, webhookURL := range urls {
+		if webhookURL == "" || seen[webhookURL] {
+			continue
+		}
+		seen[webhookURL] = true
+
+		dest := h.destinationFor(webhookURL)
+		if dest.recordProbe(h.probeWebhook(webhookURL)) && h.probe.onChange != nil {
+			h.probe.onChange(dest.snapshot(webhookURL, h.rateLimits.Blocked(webhookURL)))
+		}
+	}
+}
+
+// probeWebhook fetches the webhook object, see https://discord.com/developers/docs/resources/webhook
+func (h *Hook) probeWebhook(webhookURL string) error {
+	respons, _, err := h.sender().Get(context.Background(), webhookURL, webhookURL)
+	if err != nil {
+		return eris.Wrap(err, "webhook health probe failed")
+	}
+
+	// Rate limit berarti webhook masih ada, jadi tidak dianggap rusak
+	if respons.StatusCode >= 300 && respons.StatusCode != http.StatusTooManyRequests {
+		return sender.APIError(respons)
+	}
+	return nil
+}
+
+// recordProbe stores the result of a health probe and reports whether the health changed
+func (d *destination) recordProbe(err error) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	wasUnhealthy := d.probeError != ""
+	d.lastProbe = time.Now()
+	d.probeError = ""
+	if err != nil {
+		d.probeError = err.Error()
+	}
+	return wasUnhealthy != (err != nil)
+}
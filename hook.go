@@ -8,6 +8,13 @@
 //	logger.AddHook(hook)
 //	logger.Error("Something went wrong")
 //
+// Configuration with options:
+//
+//	hook := discord.NewHook(webhookURL,
+//		discord.WithLevels(logrus.ErrorLevel, logrus.FatalLevel),
+//		discord.WithUsername("billing-api"),
+//	)
+//
 // HTTP request logging:
 //
 //	logger.WithField(discord.RequestFieldKey, discord.LoggerHttpRequestPayload{
@@ -28,12 +35,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"mime/multipart"
 	"net/http"
-	"net/url"
-	"strings"
+	"net/netip"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/murbagus/discordrus/sender"
 	"github.com/rotisserie/eris"
 	"github.com/sirupsen/logrus"
 )
@@ -41,6 +49,16 @@ import (
 const (
 	// REQUEST_FIELD_KEY is the key used to access HTTP request data in logrus fields
 	REQUEST_FIELD_KEY = "request"
+
+	// maxPreviewLength is the number of characters shown inline when the
+	// message is moved to an attachment
+	maxPreviewLength = 300
+
+	// defaultUsername is the webhook display name used when Hook.Username is empty
+	defaultUsername = "Golang"
+
+	// DefaultTimeout is the time a single request to Discord may take, see WithTimeout
+	DefaultTimeout = sender.DefaultTimeout
 )
 
 // LoggerHttpRequestPayload holds HTTP request information for logging
@@ -54,46 +72,318 @@ type LoggerHttpRequestPayload struct {
 	URL        string // Request URL
 	BodyString string // Request body as string
 	Headers    string // Request headers as string
+
+	// Optional routing details, e.g. filled by the ginhook and echohook packages
+	ClientIP   string            // Address of the client
+	PathParams map[string]string // Path parameters of the matched route, e.g. {"id": "42"}
 }
 
 // Hook represents a Discord webhook hook for Logrus
 type Hook struct {
 	HookUrl string
-	lvl     []logrus.Level
+
+	// SigningSecret enables HMAC-SHA256 signing of the outgoing webhook body
+	// when not empty. See SignatureHeader and TimestampHeader.
+	SigningSecret string
+
+	// Username overrides the webhook's display name, defaults to "Golang"
+	Username string
+	// AvatarURL overrides the webhook's avatar when not empty
+	AvatarURL string
+
+	// Async delivers entries in a background goroutine (the default for hooks created
+	// with NewHook). When false, Fire blocks until the entry has been delivered and
+	// returns the delivery error, so logs written right before exiting are not lost.
+	Async bool
+
+	// Client is used to deliver webhook requests when not nil, e.g. to configure
+	// proxies, TLS or instrumented transports. Certificates added with WithClientCert
+	// only apply when Client is nil.
+	Client *http.Client
+
+	// OnError is called with every asynchronous delivery error and the entry that
+	// could not be delivered, e.g. to count failures or fall back to another sink.
+	// Entries dropped because the queue is full are reported with ErrQueueFull. The entry
+	// is nil for digests and drop reports, see WithDigest and WithoutDropReports. Errors
+	// are printed to stdout when it is nil.
+	OnError func(err error, entry *logrus.Entry)
+
+	// OnDelivered is called with the message Discord created for an entry, e.g. to store
+	// which message an internal error ID was reported in. Batched entries share one result,
+	// edits of progress messages are reported as well. It is called from the delivering
+	// goroutine and should not block. Setting it makes every delivery wait for the created
+	// message, and the first delivery to a webhook fetches the webhook to link the message.
+	OnDelivered func(result DeliveryResult, entry *logrus.Entry)
+
+	// FallbackWriter, if set, receives every message that could not be delivered to
+	// Discord, e.g. a local file for later inspection. Each message is written as one
+	// line of JSON with the time, the redacted webhook URL, the error and the payload.
+	FallbackWriter io.Writer
+
+	lvl                []logrus.Level
+	transport          *http.Transport
+	exportDir          string
+	maxAttachmentSize  int64
+	payloadFormatter   PayloadFormatter
+	payloadVersion     PayloadVersion
+	colors             ColorScheme
+	identity           identity
+	backfillAfter      time.Duration
+	maxAlertAges       map[logrus.Level]maxAlertAge
+	progressField      string
+	excludedFields     map[string]bool
+	fieldRenderings    map[string]FieldRendering
+	fieldOrder         []string
+	inlineFields       map[string]bool
+	defaultInline      *bool
+	callerTrimPrefixes []string
+	jsonSummaryAfter   int
+	maxBodySize        int
+	headerRendering    *headerRendering
+	headerValueLength  int
+	errorDetail        *errorDetail
+	stackTrace         bool
+	captureModes       map[logrus.Level]CaptureMode
+	redaction          RedactionRules
+	verifyDelivery     bool
+	timeout            time.Duration
+	retry              RetryPolicy
+	rateLimits         sender.RateLimits
+	limits             EmbedLimits
+	threadID           string
+	threadName         func(entry *logrus.Entry) string
+	appliedTags        []string
+	tagIDs             map[string]string
+	probe              *healthProbe
+	noWellKnownFields  bool
+	trustedProxies     []netip.Prefix
+	clientInfo         bool
+	curlCommand        bool
+	harAttachment      bool
+	goroutineDump      bool
+	systemInfo         bool
+	templates          embedTemplates
+	labels             *Labels
+	componentsV2       bool
+	customSender       Sender
+	traceIDField       string
+	spanEvents         bool
+	sampling           map[logrus.Level]*sampler
+	digest             *digest
+	progress           progressTracker
+	categoryField      string
+	categoryRoutes     map[string]string
+	levelRoutes        map[logrus.Level]string
+
+	levelsMu  sync.Mutex
+	levelMask atomic.Uint32 // bit per level yang diproses, dibaca tanpa lock di Fire
+	mutes     mutes
+	persist   *persistentQueue
+
+	destinationsMu sync.Mutex
+	destinations   map[string]*destination
+
+	pending  inflight
+	closed   atomic.Bool
+	batch    *batcher
+	coalesce *coalescer
+	dedup    *deduplicator
+	pool     workerPool
+	drops    dropCounter
+	metrics  metrics
+
+	fallbackMu sync.Mutex
 }
 
-// NewHook creates a new Discord webhook hook for Logrus
-// You can specify the webhook URL and optional log levels to filter
-// If no levels are specified, it defaults to Panic, Fatal, Error, and Warn levels
-func NewHook(webhookURL string, levels ...logrus.Level) *Hook {
-	lvl := levels
-	if len(lvl) == 0 {
-		lvl = []logrus.Level{
-			logrus.PanicLevel,
-			logrus.FatalLevel,
-			logrus.ErrorLevel,
-			logrus.WarnLevel,
-		}
+// NewHook creates a new Discord webhook hook for Logrus configured with the given options,
+// e.g. WithLevels, WithUsername, WithTimeout or WithFormatter
+// If no levels are configured, it defaults to Panic, Fatal, Error, and Warn levels
+func NewHook(webhookURL string, opts ...Option) *Hook {
+	h := &Hook{
+		HookUrl:   webhookURL,
+		Async:     true,
+		redaction: DefaultRedactionRules(),
+		timeout:   DefaultTimeout,
+		retry:     DefaultRetryPolicy,
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
 
-	return &Hook{
-		HookUrl: webhookURL,
-		lvl:     lvl,
+	h.SetLevels(h.lvl)
+	if h.probe != nil {
+		h.startProbe()
+	}
+	if h.digest != nil {
+		h.startDigest()
+	}
+	if h.persist != nil {
+		h.startReplay()
 	}
+
+	return h
+}
+
+// NewHookWithLevels creates a new Discord webhook hook for the given levels, the signature
+// of NewHook before it accepted options
+// If no levels are specified, it defaults to Panic, Fatal, Error, and Warn levels
+func NewHookWithLevels(webhookURL string, levels ...logrus.Level) *Hook {
+	return NewHook(webhookURL, WithLevels(levels...))
+}
+
+// NewHookWithOptions creates a new Discord webhook hook configured with the given options
+//
+// Deprecated: NewHook accepts the same options.
+func NewHookWithOptions(webhookURL string, opts ...Option) *Hook {
+	return NewHook(webhookURL, opts...)
 }
 
-// Levels returns the log levels that this hook will process
+// Levels returns every log level, so the levels the hook processes can be changed with
+// SetLevels while it is registered; Fire ignores entries of the other levels.
+// EnabledLevels returns the levels that are processed.
 func (h *Hook) Levels() []logrus.Level {
-	return h.lvl
+	return append([]logrus.Level(nil), AllLevels...)
 }
 
 // Fire is called when a log event occurs
 func (h *Hook) Fire(entry *logrus.Entry) error {
-	if h.HookUrl == "" {
-		return eris.New("Discord webhook url is empty")
+	// Levels() mencakup semua level, jadi level yang tidak aktif diabaikan sebelum pengecekan lain
+	if entry != nil && entry.Level <= logrus.TraceLevel && (skipped(entry) || (!h.levelEnabled(entry.Level) && !forceSend(entry))) {
+		return nil
+	}
+	if h.closed.Load() {
+		return ErrHookClosed
+	}
+	if err := validateEntry(entry); err != nil {
+		return err
+	}
+
+	webhookURL := h.webhookURLFor(entry)
+	if webhookURL == "" && h.exportDir == "" {
+		return ErrWebhookEmpty
+	}
+
+	// Buat salinan entry beserta data entry.Data["request"] jika ada
+	snapshot := h.snapshotEntry(entry)
+	fingerprint := h.fingerprint(snapshot)
+	h.recordSpanEvent(snapshot, fingerprint)
+	if h.muted(fingerprint) {
+		return nil
+	}
+	// Entry ini tetap dikirim, entry berikutnya di-mute setelah pengiriman berhasil
+	mute, _ := muteFor(snapshot)
+	if h.digest != nil && h.digest.covers(entry.Level) {
+		h.digest.add(snapshot, webhookURL, fingerprint)
+		return nil
+	}
+
+	rate, keep := h.sample(entry.Level)
+	if !keep {
+		return nil
+	}
+	record, repeated := h.dedup.track(snapshot, webhookURL)
+	if repeated {
+		return nil
+	}
+	// Nomor urut diambil di sini agar update progress yang terlambat bisa dikenali
+	var progressSeq uint64
+	if key, _ := h.progressKey(snapshot); key != "" {
+		progressSeq = h.progress.next(webhookURL + "\x00" + key)
+	}
+	prepare := func() (*message, error) {
+		msg, err := h.prepare(snapshot, webhookURL)
+		if err != nil {
+			record.release()
+			return nil, err
+		}
+		msg.dedup = record
+		msg.progressSeq = progressSeq
+		msg.muteKey, msg.muteFor = fingerprint, mute
+		if rate > 1 {
+			h.annotateTitle(msg, fmt.Sprintf(" (sampled 1/%d)", rate))
+		}
+		return msg, nil
 	}
 
-	// Buat salinan data dari entry.Data["request"] jika ada
+	h.pending.add()
+	if !h.Async {
+		defer h.pending.done()
+		msg, err := prepare()
+		if err != nil {
+			return err
+		}
+		if err := h.deliver(msg); err != nil {
+			return err
+		}
+		h.muteDelivered(msg)
+		return nil
+	}
+
+	h.pool.enqueue(job{entry: snapshot, url: webhookURL, run: func() {
+		msg, err := prepare()
+		if err == nil && msg.progressKey == "" {
+			// pending.done dipanggil setelah pesan terkirim
+			if h.coalesce != nil {
+				h.coalesce.add(msg)
+				return
+			}
+			if h.batch != nil {
+				h.batch.add(msg)
+				return
+			}
+		}
+		defer h.pending.done()
+
+		if err == nil {
+			err = h.deliver(msg)
+		}
+		if err != nil {
+			h.reportError(err, snapshot)
+			return
+		}
+		h.muteDelivered(msg)
+	}, cancel: record.release}, h.dropJob)
+
+	return nil
+}
+
+// dropJob reports an asynchronous entry that did not fit into the queue
+func (h *Hook) dropJob(j job) {
+	j.cancel()
+	h.pending.done()
+	h.countDrop(j.url)
+	h.reportError(ErrQueueFull, j.entry)
+}
+
+// reportError passes an asynchronous delivery error to OnError
+// Errors of entries built by BuildPayload are not reported.
+func (h *Hook) reportError(err error, entry *logrus.Entry) {
+	if entry != nil && isPreview(entry.Context) {
+		return
+	}
+	if h.OnError != nil {
+		h.OnError(err, entry)
+		return
+	}
+	fmt.Println(err.Error())
+}
+
+// prepare builds the message for the entry, addressed to webhookURL
+func (h *Hook) prepare(entry *logrus.Entry, webhookURL string) (*message, error) {
+	msg, err := h.buildMessage(entry)
+	if err != nil {
+		return nil, err
+	}
+	msg.URL = webhookURL
+	msg.entry = entry
+	msg.progressKey, msg.progressDone = h.progressKey(entry)
+	return msg, nil
+}
+
+// captureRequestPayload makes a copy of the request payload in entry.Data[REQUEST_FIELD_KEY]
+// so it stays valid after the caller has finished the request. It returns nil if there is none.
+// The body is only copied when withBody is true.
+func captureRequestPayload(entry *logrus.Entry, withBody bool) *LoggerHttpRequestPayload {
 	var dataRequestPayload *LoggerHttpRequestPayload
 	if v, k := entry.Data[REQUEST_FIELD_KEY]; k {
 		if valReq, ok := v.(LoggerHttpRequestPayload); ok {
@@ -107,313 +397,193 @@ func (h *Hook) Fire(entry *logrus.Entry) error {
 
 				// Membuat copy body jika tersedia
 				var bodyBytes []byte
-				if valReq.Request.Body != nil {
+				if !withBody {
+					dataRequestPayload.Request.Body = http.NoBody
+				} else if valReq.Request.Body != nil && valReq.Request.Body != http.NoBody {
 					bodyBytes, _ = io.ReadAll(valReq.Request.Body)
 
 					// Kembalikan body ke ReadCloser agar kode berikutnya bisa membacanya
 					valReq.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+
+					// Body terkompresi di-decode, header dihapus agar tidak di-decode dua kali
+					if decoded, ok := decodeBody(bodyBytes, valReq.Request.Header.Get("Content-Encoding")); ok {
+						bodyBytes = decoded
+						dataRequestPayload.Request.Header.Del("Content-Encoding")
+						dataRequestPayload.Request.ContentLength = int64(len(bodyBytes))
+					}
 					dataRequestPayload.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes)) // Kembalikan body ke ReadCloser
 				}
 			} else {
 				dataRequestPayload = &LoggerHttpRequestPayload{
-					Method:     valReq.Method,
-					URL:        valReq.URL,
-					BodyString: valReq.BodyString,
-					Headers:    valReq.Headers,
+					Method:  valReq.Method,
+					URL:     valReq.URL,
+					Headers: valReq.Headers,
+				}
+				if withBody {
+					dataRequestPayload.BodyString = valReq.BodyString
 				}
 			}
-
-		}
-	}
-
-	go func(drp *LoggerHttpRequestPayload) {
-		errorMessage := ""
-		if v, k := entry.Data["error"]; k {
-			if errVal, ok := v.(error); ok {
-				errorMessage = errVal.Error()
-			} else if errVal, ok := v.(string); ok {
-				errorMessage = errVal
+			dataRequestPayload.ClientIP = valReq.ClientIP
+			if len(valReq.PathParams) > 0 {
+				dataRequestPayload.PathParams = make(map[string]string, len(valReq.PathParams))
+				for k, v := range valReq.PathParams {
+					dataRequestPayload.PathParams[k] = v
+				}
 			}
-		}
 
-		embedCollor := 12434877 // Warna default
-		switch entry.Level {
-		case logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel:
-			embedCollor = 16725591
-		case logrus.WarnLevel:
-			embedCollor = 16760630
-		default:
-			embedCollor = 12434877
 		}
+	}
 
-		// Request payload fields
-		fields := []map[string]interface{}{}
-
-		// Menambahkan request payload field jika tersedia dalam entry.Data["request"]
-		if drp != nil {
-			if drp.Request != nil {
-				fields = append(fields,
-					map[string]any{
-						"name":  "Method",
-						"value": "```" + drp.Request.Method + " ```",
-					},
-					map[string]any{
-						"name":  "URL",
-						"value": "```" + drp.Request.URL.String() + " ```",
-					},
-				)
-
-				// Menambahkan mody sesuai dengan content-type
-				var bodyBytes []byte
-				if drp.Request.Body != nil {
-					bodyBytes, _ = io.ReadAll(drp.Request.Body)
-
-					// Kembalikan body ke ReadCloser agar kode berikutnya bisa membacanya
-					drp.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-				}
-
-				contentType := drp.Request.Header.Get("Content-Type")
-				switch {
-				case strings.Contains(contentType, "application/json"):
-					fields = append(fields, map[string]any{
-						"name":  "Body",
-						"value": "```" + string(bodyBytes) + " ```",
-					})
-
-				case strings.Contains(contentType, "multipart/form-data"):
-					// Untuk multipart, kita tidak bisa dengan mudah membaca semua bagian file ke string.
-					// Lebih baik parse form-nya dan catat hanya field non-file.
-					// Batas memori untuk parsing form: sesuaikan sesuai kebutuhan
-					const maxMemory = 32 << 20 // 32 MB
-					if err := drp.Request.ParseMultipartForm(maxMemory); err != nil && err != http.ErrNotMultipart {
-						fields = append(fields, map[string]any{
-							"name":  "Body",
-							"value": "```" + err.Error() + "```",
-						})
-					} else {
-						formData := make(map[string]any)
-						for key, values := range drp.Request.MultipartForm.Value {
-							if len(values) > 1 {
-								formData[key] = values // Bisa jadi slice of strings
-							} else {
-								formData[key] = values[0] // Bisa jadi slice of strings
-							}
-						}
-						// Jangan log FileHeader secara langsung karena berisi metadata file
-						// Anda bisa menambahkan logic untuk mencatat nama file atau ukuran jika diperlukan
-						// Misalnya:
-						fileInfo := make(map[string]any)
-						for key, files := range drp.Request.MultipartForm.File {
-							if len(files) > 1 {
-								var fileNames []string
-								var fileSize []string
-								for _, fileHeader := range files {
-									fileNames = append(fileNames, fileHeader.Filename)
-									fileSize = append(fileSize, fmt.Sprintf("%.2f KB", float64(fileHeader.Size)/1024))
-								}
-
-								fileInfo[key] = map[string]any{
-									"nama":   fileNames,
-									"ukuran": fileSize,
-								}
-							} else {
-								fileInfo[key] = map[string]any{
-									"nama":   files[0].Filename,
-									"ukuran": fmt.Sprintf("%.2f KB", float64(files[0].Size)/1024),
-								}
-							}
-						}
-						// 1. Gabungkan formData dan fileInfo ke dalam satu map
-						combinedData := make(map[string]any)
-						if len(formData) > 0 {
-							combinedData["form_fields"] = formData
-						}
-						if len(fileInfo) > 0 {
-							combinedData["uploaded_files"] = fileInfo
-						}
-
-						// 2. Ubah combinedData menjadi string JSON
-						jsonString, err := json.MarshalIndent(combinedData, "", "  ") // Gunakan MarshalIndent untuk output yang rapi
-						if err == nil {
-							fields = append(fields, map[string]any{
-								"name":  "Body",
-								"value": "```" + string(jsonString) + "```",
-							})
-						}
-					}
-
-				case strings.Contains(contentType, "application/x-www-form-urlencoded"):
-					if len(bodyBytes) > 0 {
-						parsedForm, err := url.ParseQuery(string(bodyBytes))
-						if err != nil {
-							fields = append(fields, map[string]any{
-								"name":  "Body",
-								"value": "```" + string(bodyBytes) + " ```",
-							})
-						} else {
-							formData := make(map[string]interface{})
-							for key, values := range parsedForm {
-								formData[key] = values
-							}
-							jsonString, err := json.MarshalIndent(formData, "", "  ") // Gunakan MarshalIndent untuk output yang rapi
-							if err == nil {
-								fields = append(fields, map[string]any{
-									"name":  "Body",
-									"value": "```" + string(jsonString) + "```",
-								})
-							}
-						}
-					}
+	return dataRequestPayload
+}
 
-				default:
-					// Untuk Content-Type lain, catat body mentah jika tidak terlalu besar
-					// Pertimbangkan ukuran maksimum untuk logging raw body
-					const maxRawBodyLogSize = 1024 // 1 KB
-					if len(bodyBytes) > 0 {
-						if len(bodyBytes) <= maxRawBodyLogSize {
-							fields = append(fields, map[string]any{
-								"name":  "Body",
-								"value": "```" + string(bodyBytes) + " ```",
-							})
-						}
-					}
-				}
-			} else {
-				if drp.Method != "" {
-					fields = append(fields, map[string]interface{}{
-						"name":  "Method",
-						"value": "```" + drp.Method + " ```",
-					})
-				}
-				if drp.URL != "" {
-					fields = append(fields, map[string]interface{}{
-						"name":  "URL",
-						"value": "```" + drp.URL + " ```",
-					})
-				}
-				if drp.BodyString != "" {
-					fields = append(fields, map[string]interface{}{
-						"name":  "Body",
-						"value": "```" + drp.BodyString + " ```",
-					})
-				}
-				if drp.Headers != "" {
-					fields = append(fields, map[string]interface{}{
-						"name":  "Headers",
-						"value": "```" + drp.Headers + " ```",
-					})
-				}
-			}
-		}
+// snapshotEntry copies the entry so it can be formatted after Fire has returned
+// The request and response payloads are replaced by copies that stay valid after the request has finished,
+// captured according to the capture mode of the entry's level and redacted. Entries logged with
+// a context from Middleware get the request stored in it.
+func (h *Hook) snapshotEntry(entry *logrus.Entry) *logrus.Entry {
+	snapshot := h.copyEntry(entry)
+	h.addGoroutineDump(snapshot)
+	h.addSystemInfo(snapshot)
+	return snapshot
+}
 
-		// Jika entry.Message terlalu panjang, kirim sebagai file attachment (txt)
-		const maxMessageLength = 500 // Discord embed description max is 4096, tapi biar aman
-		messageToSend := entry.Message
-		sendAsFile := len(messageToSend) > maxMessageLength
-
-		embeds := []map[string]any{
-			{
-				"title":       strings.ToUpper(entry.Level.String()),
-				"description": errorMessage,
-				"timestamp":   entry.Time.UTC().Format(time.RFC3339),
-				"color":       embedCollor,
-			},
-			{
-				"title":  "REQUEST PAYLOAD",
-				"fields": fields,
-				"color":  embedCollor,
-			},
+// copyEntry returns the copy of the entry with the captured payloads redacted, without the
+// goroutine dump and system snapshot that snapshotEntry adds
+func (h *Hook) copyEntry(entry *logrus.Entry) *logrus.Entry {
+	snapshot := *entry
+	snapshot.Data = make(logrus.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		snapshot.Data[k] = v
+	}
+	normalizeEntry(&snapshot)
+	// Request dari context dipakai jika entry tidak membawa request sendiri
+	if _, ok := snapshot.Data[REQUEST_FIELD_KEY]; !ok {
+		if p, ok := RequestFromContext(entry.Context); ok {
+			snapshot.Data[REQUEST_FIELD_KEY] = p
 		}
+	}
 
-		if !sendAsFile {
-			embeds = append(embeds, map[string]any{
-				"title":       "MESSAGE",
-				"description": "```" + messageToSend + " ```",
-				"color":       embedCollor,
-			})
+	mode := h.captureModeFor(entry.Level)
+	if mode == CaptureNone {
+		delete(snapshot.Data, REQUEST_FIELD_KEY)
+		delete(snapshot.Data, RESPONSE_FIELD_KEY)
+	} else {
+		if drp := captureRequestPayload(&snapshot, mode == CaptureFull); drp != nil {
+			h.redaction.redactPayload(drp)
+			snapshot.Data[REQUEST_FIELD_KEY] = *drp
+		} else if grpcReq := captureGrpcPayload(&snapshot, mode == CaptureFull); grpcReq != nil {
+			h.redaction.redactGrpcRequest(grpcReq)
+			snapshot.Data[REQUEST_FIELD_KEY] = *grpcReq
 		}
-
-		payload, err := json.Marshal(map[string]any{
-			"username": "Golang",
-			"embeds":   embeds,
-		})
-		if err != nil {
-			fmt.Println("Failed to marshal Discord webhook payload:", err)
-			return
+		if resp := captureResponsePayload(entry, mode == CaptureFull); resp != nil {
+			h.redaction.redactResponse(resp)
+			snapshot.Data[RESPONSE_FIELD_KEY] = *resp
 		}
+	}
+	snapshot.Message = h.redaction.redactText(snapshot.Message)
+	return &snapshot
+}
 
-		if sendAsFile {
-			var b bytes.Buffer
-			w := io.MultiWriter(&b)
-
-			// Tulis pesan log ke file txt
-			_, _ = w.Write([]byte(messageToSend))
-
-			// Buat multipart writer
-			var body bytes.Buffer
-			mp := multipart.NewWriter(&body)
-
-			// Tambahkan payload_json field
-			part, err := mp.CreateFormField("payload_json")
-			if err != nil {
-				fmt.Println("Failed to create multipart field:", err)
-				return
-			}
-			_, _ = part.Write(payload)
-
-			// Tambahkan file attachment
-			filePart, err := mp.CreateFormFile("files[0]", "log.txt")
-			if err != nil {
-				fmt.Println("Failed to create multipart file:", err)
-				return
+// buildMessage renders the log entry with the configured formatter into a webhook message
+func (h *Hook) buildMessage(entry *logrus.Entry) (*message, error) {
+	payload, err := h.buildPayload(entry)
+	if err != nil {
+		return nil, err
+	}
+	msg, err := h.newMessage(payload)
+	if err != nil {
+		return nil, err
+	}
+	msg.Files = append(msg.Files, entryAttachments(entry)...)
+	// Discord menolak pesan dengan lebih dari 10 file, sisanya tidak dikirim
+	if len(msg.Files) > maxFilesPerMessage {
+		h.reportError(eris.Errorf("%d attachment(s) dropped, Discord accepts at most %d files per message", len(msg.Files)-maxFilesPerMessage, maxFilesPerMessage), entry)
+		for _, f := range msg.Files[maxFilesPerMessage:] {
+			if f.source != nil {
+				f.source.close()
 			}
-			_, _ = filePart.Write(b.Bytes())
+		}
+		msg.Files = msg.Files[:maxFilesPerMessage]
+	}
+	return msg, nil
+}
 
-			mp.Close()
+// buildPayload renders the log entry with the configured formatter, with the thread name and
+// tags of the hook
+func (h *Hook) buildPayload(entry *logrus.Entry) (*WebhookPayload, error) {
+	payload, err := h.formatter().Format(entry)
+	if err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		return nil, eris.New("formatter returned no payload")
+	}
 
-			request, err := http.NewRequest("POST", h.HookUrl, &body)
-			if err != nil {
-				fmt.Println(err.Error())
-				return
-			}
-			request.Header.Set("Content-Type", mp.FormDataContentType())
+	if payload.ThreadName == "" && h.threadName != nil {
+		payload.ThreadName = h.threadName(entry)
+	}
+	if len(payload.AppliedTags) == 0 {
+		payload.AppliedTags = h.appliedTagsFor(entry)
+	}
+	return payload, nil
+}
 
-			client := &http.Client{}
-			respons, err := client.Do(request)
-			if err != nil {
-				fmt.Println(err.Error())
-				return
-			}
-			defer respons.Body.Close()
+// newMessage encodes the payload with the hook's identity and embed limits applied
+func (h *Hook) newMessage(payload *WebhookPayload) (*message, error) {
+	// Identitas webhook diatur di level hook, formatter boleh menimpanya
+	if payload.Username == "" {
+		payload.Username = h.Username
+		if payload.Username == "" {
+			payload.Username = defaultUsername
+		}
+	}
+	if payload.AvatarURL == "" {
+		payload.AvatarURL = h.AvatarURL
+	}
+	h.limits.apply(payload)
 
-			if respons.StatusCode >= 300 {
-				fmt.Println("Failed to post to Discord webhook")
-				return
-			}
-			return
-		} else {
-			request, err := http.NewRequest("POST", h.HookUrl, bytes.NewBuffer(payload))
-			request.Header.Set("Content-Type", "application/json")
-			if err != nil {
-				fmt.Println(err.Error())
-				return
-			}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to marshal Discord webhook payload")
+	}
 
-			client := &http.Client{}
-			respons, err := client.Do(request)
-			if err != nil {
-				fmt.Println(err.Error())
-				return
-			}
-			defer respons.Body.Close()
+	msg := &message{Payload: data, threadID: h.threadID, threadName: payload.ThreadName}
+	for _, a := range payload.Files {
+		msg.Files = append(msg.Files, attachment{Name: a.Name, source: newAttachmentSource(a)})
+	}
+	return msg, nil
+}
 
-			if respons.StatusCode >= 300 {
-				fmt.Println("Failed to post to Discord webhook")
-				return
-			}
-		}
+// sender returns the delivery layer configured with the hook's current settings
+// The rate limit state is shared by all deliveries of the hook.
+func (h *Hook) sender() *sender.Sender {
+	return sender.New(
+		sender.WithHTTPClient(h.httpClient()),
+		sender.WithTimeout(h.timeout),
+		sender.WithRetryPolicy(h.retry),
+		sender.WithSigningSecret(h.SigningSecret),
+		sender.WithRateLimits(&h.rateLimits),
+	)
+}
 
-	}(dataRequestPayload)
+// httpClient returns the client used to deliver webhook requests
+func (h *Hook) httpClient() *http.Client {
+	if h.Client != nil {
+		return h.Client
+	}
+	if h.transport != nil {
+		return &http.Client{Transport: h.transport}
+	}
+	return &http.Client{}
+}
 
-	return nil
+// previewText returns the first n characters (runes) of s, followed by an
+// ellipsis marker if s was cut
+func previewText(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n]) + "…"
 }
@@ -0,0 +1,54 @@
+package discordrus
+
+import (
+	"crypto/tls"
+	"errors"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestClosedHookIgnoresDisabledLevels(t *testing.T) {
+	h := NewHook("https://discord.com/api/webhooks/1/token", WithLevels(logrus.ErrorLevel))
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	tests := []struct {
+		name  string
+		entry *logrus.Entry
+		want  error
+	}{
+		{"info", &logrus.Entry{Logger: logger, Level: logrus.InfoLevel, Data: logrus.Fields{}}, nil},
+		{"debug", &logrus.Entry{Logger: logger, Level: logrus.DebugLevel, Data: logrus.Fields{}}, nil},
+		{"trace without data", &logrus.Entry{Logger: logger, Level: logrus.TraceLevel}, nil},
+		{"skipped error", &logrus.Entry{Logger: logger, Level: logrus.ErrorLevel, Data: logrus.Fields{SKIP_FIELD_KEY: true}}, nil},
+		{"enabled level", &logrus.Entry{Logger: logger, Level: logrus.ErrorLevel, Data: logrus.Fields{}}, ErrHookClosed},
+		{"forced info", &logrus.Entry{Logger: logger, Level: logrus.InfoLevel, Data: logrus.Fields{FORCE_SEND_FIELD_KEY: true}}, ErrHookClosed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := h.Fire(tt.entry); !errors.Is(err, tt.want) {
+				t.Errorf("Fire() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+type wrappedTransport struct{ http.RoundTripper }
+
+func TestClientCertWithWrappedDefaultTransport(t *testing.T) {
+	original := http.DefaultTransport
+	http.DefaultTransport = wrappedTransport{original}
+	t.Cleanup(func() { http.DefaultTransport = original })
+
+	h := NewHook("https://discord.com/api/webhooks/1/token", WithClientCert(tls.Certificate{}))
+	if h.transport == nil || len(h.transport.TLSClientConfig.Certificates) != 1 {
+		t.Fatal("client certificate was not added to the hook's transport")
+	}
+}
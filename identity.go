@@ -0,0 +1,59 @@
+package discordrus
+
+import (
+	"os"
+	"strings"
+)
+
+// identity describes the service instance producing the logs
+type identity struct {
+	appName     string
+	appVersion  string
+	environment string
+	hostname    string
+}
+
+// WithAppName shows the application name in the footer of the level embed,
+// so services sharing a channel can be told apart
+func WithAppName(name string) Option {
+	return func(h *Hook) {
+		h.identity.appName = name
+	}
+}
+
+// WithAppVersion shows the application version next to the application name
+func WithAppVersion(version string) Option {
+	return func(h *Hook) {
+		h.identity.appVersion = version
+	}
+}
+
+// WithEnvironment shows the deployment environment (e.g. "production") in the footer
+func WithEnvironment(env string) Option {
+	return func(h *Hook) {
+		h.identity.environment = env
+	}
+}
+
+// WithHostname shows the host name in the footer
+// An empty hostname uses the machine's host name as reported by os.Hostname.
+func WithHostname(hostname string) Option {
+	return func(h *Hook) {
+		if hostname == "" {
+			hostname, _ = os.Hostname()
+		}
+		h.identity.hostname = hostname
+	}
+}
+
+// footer returns the footer text, e.g. "billing-api v1.4.2 · production · web-3"
+func (i identity) footer() string {
+	var parts []string
+	app := strings.TrimSpace(i.appName + " " + i.appVersion)
+	for _, p := range []string{app, i.environment, i.hostname} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " · ")
+}
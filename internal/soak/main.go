@@ -0,0 +1,255 @@
+//go:build soak
+
+// Command soak fires a large number of entries through the hook against a mock Discord
+// server and checks that memory, goroutines and request and response bodies stay bounded.
+// It validates the queueing and pooling of the hook under load and exits with status 1
+// when a guardrail is violated.
+//
+// Usage:
+//
+//	go run -tags soak ./internal/soak -entries 300000 -producers 64
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/murbagus/discordrus"
+	"github.com/sirupsen/logrus"
+)
+
+func main() {
+	entries := flag.Int("entries", 300000, "number of entries to fire")
+	producers := flag.Int("producers", 64, "number of goroutines firing entries")
+	failEvery := flag.Int("fail-every", 50, "answer every n-th request with HTTP 500, 0 disables failures")
+	limitEvery := flag.Int("limit-every", 200, "answer every n-th request with HTTP 429, 0 disables rate limits")
+	maxHeap := flag.Int("max-heap-mb", 256, "upper bound of the heap while firing, in MB")
+	maxRetained := flag.Int("max-retained-mb", 16, "upper bound of the heap retained after Close, in MB")
+	policy := flag.String("policy", "drop_newest", "overflow policy: drop_newest, drop_oldest or block")
+	flag.Parse()
+
+	overflow := map[string]discordrus.OverflowPolicy{
+		"drop_newest": discordrus.OverflowDropNewest,
+		"drop_oldest": discordrus.OverflowDropOldest,
+		"block":       discordrus.OverflowBlock,
+	}[*policy]
+
+	server := newMockDiscord(*failEvery, *limitEvery)
+	defer server.Close()
+	transport := &trackingTransport{base: &http.Transport{MaxIdleConnsPerHost: 32}}
+
+	baseHeap := heapInUse()
+	baseGoroutines := runtime.NumGoroutine()
+
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+	hook := discordrus.NewHook(server.URL+"/api/webhooks/1/soak",
+		discordrus.WithLevels(discordrus.AllLevels...),
+		discordrus.WithHTTPClient(&http.Client{Transport: transport}),
+		discordrus.WithRetryPolicy(discordrus.RetryPolicy{MaxRetries: 2, BaseDelay: time.Millisecond}),
+		discordrus.WithWorkerPool(8, 1024, overflow),
+		discordrus.WithoutDropReports(),
+	)
+	var asyncErrors atomic.Int64
+	hook.OnError = func(error, *logrus.Entry) { asyncErrors.Add(1) }
+	logger.AddHook(hook)
+
+	// Catat puncak heap dan goroutine selama entry dikirim
+	var peakHeap, peakGoroutines atomic.Uint64
+	stopSampling := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		ticker := time.NewTicker(50 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				storeMax(&peakHeap, heapInUse())
+				storeMax(&peakGoroutines, uint64(runtime.NumGoroutine()))
+			case <-stopSampling:
+				return
+			}
+		}
+	}()
+
+	start := time.Now()
+	var wg sync.WaitGroup
+	perProducer := *entries / *producers
+	for p := 0; p < *producers; p++ {
+		wg.Add(1)
+		go func(p int) {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				fire(logger, p, i)
+			}
+		}(p)
+	}
+	wg.Wait()
+	fired := time.Since(start)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+	if err := hook.Flush(ctx); err != nil {
+		fmt.Fprintln(os.Stderr, "flush:", err)
+		os.Exit(1)
+	}
+	hook.Close()
+	close(stopSampling)
+	<-sampled
+	transport.base.CloseIdleConnections()
+
+	// Beri waktu goroutine koneksi dan timer untuk selesai
+	time.Sleep(500 * time.Millisecond)
+	retainedHeap := heapInUse()
+	goroutines := runtime.NumGoroutine()
+
+	m := hook.Metrics()
+	fmt.Printf("fired %d entries in %s (%d producers, %s)\n", perProducer**producers, fired.Round(time.Millisecond), *producers, *policy)
+	fmt.Printf("sent %d, failed %d, retried %d, rate limited %d, dropped %d, async errors %d\n",
+		m.Sent, m.Failed, m.Retried, m.RateLimited, m.Dropped, asyncErrors.Load())
+	fmt.Printf("server received %d requests\n", server.requests.Load())
+	fmt.Printf("heap: base %s, peak %s, retained %s\n", mb(baseHeap), mb(peakHeap.Load()), mb(retainedHeap))
+	fmt.Printf("goroutines: base %d, peak %d, after close %d\n", baseGoroutines, peakGoroutines.Load(), goroutines)
+	fmt.Printf("request bodies open %d, response bodies open %d\n", transport.openRequests.Load(), transport.openResponses.Load())
+
+	var violations []string
+	if peak := peakHeap.Load(); peak > baseHeap+uint64(*maxHeap)<<20 {
+		violations = append(violations, fmt.Sprintf("peak heap %s exceeds %d MB", mb(peak), *maxHeap))
+	}
+	if retainedHeap > baseHeap+uint64(*maxRetained)<<20 {
+		violations = append(violations, fmt.Sprintf("retained heap %s exceeds %d MB", mb(retainedHeap), *maxRetained))
+	}
+	// Worker, timer retry dan koneksi HTTP, tapi tidak satu goroutine per entry
+	if peak := peakGoroutines.Load(); peak > uint64(baseGoroutines+*producers+256) {
+		violations = append(violations, fmt.Sprintf("peak of %d goroutines is not bounded by the worker pool", peak))
+	}
+	if goroutines > baseGoroutines+8 {
+		violations = append(violations, fmt.Sprintf("%d goroutines leaked after Close", goroutines-baseGoroutines))
+	}
+	if n := transport.openRequests.Load(); n != 0 {
+		violations = append(violations, fmt.Sprintf("%d request bodies were not closed", n))
+	}
+	if n := transport.openResponses.Load(); n != 0 {
+		violations = append(violations, fmt.Sprintf("%d response bodies were not closed", n))
+	}
+	if m.Sent+m.Failed+m.Dropped == 0 {
+		violations = append(violations, "no entry was processed")
+	}
+
+	for _, v := range violations {
+		fmt.Fprintln(os.Stderr, "FAIL:", v)
+	}
+	if len(violations) > 0 {
+		os.Exit(1)
+	}
+	fmt.Println("OK")
+}
+
+// fire logs one entry, every tenth with a request payload and body
+func fire(logger *logrus.Logger, producer, i int) {
+	entry := logger.WithFields(logrus.Fields{
+		"producer": producer,
+		"seq":      i,
+		"user_id":  fmt.Sprintf("u-%d", i%1000),
+	})
+	if i%10 == 0 {
+		req, _ := http.NewRequest(http.MethodPost, "http://shop.internal/orders", strings.NewReader(`{"order_id": 42, "items": [1, 2, 3]}`))
+		req.Header.Set("Content-Type", "application/json")
+		entry = entry.WithField(discordrus.REQUEST_FIELD_KEY, discordrus.LoggerHttpRequestPayload{Request: req})
+	}
+	entry.WithError(fmt.Errorf("soak failure %d", i%100)).Error("order processing failed")
+}
+
+// mockDiscord answers webhook requests like Discord, with failures and rate limits
+type mockDiscord struct {
+	*httptest.Server
+	requests atomic.Int64
+}
+
+func newMockDiscord(failEvery, limitEvery int) *mockDiscord {
+	m := &mockDiscord{}
+	m.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(m.requests.Add(1))
+		io.Copy(io.Discard, r.Body)
+		switch {
+		case limitEvery > 0 && n%limitEvery == 0:
+			// Sebagian hanya membawa retry_after di body JSON
+			if n%(2*limitEvery) == 0 {
+				w.Header().Set("Retry-After", "0.01")
+			}
+			w.WriteHeader(http.StatusTooManyRequests)
+			io.WriteString(w, `{"message": "You are being rate limited.", "retry_after": 0.01, "global": false}`)
+		case failEvery > 0 && n%failEvery == 0:
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	return m
+}
+
+// trackingTransport counts request and response bodies that have not been closed
+type trackingTransport struct {
+	base          *http.Transport
+	openRequests  atomic.Int64
+	openResponses atomic.Int64
+}
+
+func (t *trackingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody {
+		t.openRequests.Add(1)
+		req.Body = &trackedBody{ReadCloser: req.Body, open: &t.openRequests}
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	t.openResponses.Add(1)
+	resp.Body = &trackedBody{ReadCloser: resp.Body, open: &t.openResponses}
+	return resp, nil
+}
+
+// trackedBody decrements its counter when it is closed the first time
+type trackedBody struct {
+	io.ReadCloser
+	open   *atomic.Int64
+	closed atomic.Bool
+}
+
+func (b *trackedBody) Close() error {
+	if b.closed.CompareAndSwap(false, true) {
+		b.open.Add(-1)
+	}
+	return b.ReadCloser.Close()
+}
+
+func heapInUse() uint64 {
+	runtime.GC()
+	var s runtime.MemStats
+	runtime.ReadMemStats(&s)
+	return s.HeapInuse
+}
+
+func storeMax(v *atomic.Uint64, n uint64) {
+	for {
+		old := v.Load()
+		if n <= old || v.CompareAndSwap(old, n) {
+			return
+		}
+	}
+}
+
+func mb(n uint64) string {
+	return fmt.Sprintf("%.1f MB", float64(n)/(1<<20))
+}
@@ -0,0 +1,140 @@
+package discordrus
+
+import "reflect"
+
+// Labels are the embed titles and field names used by the default formatter, see WithLabels.
+// Notices such as "Full message attached as log.txt" are not translated.
+type Labels struct {
+	// Embed titles
+	RequestPayload   string // "REQUEST PAYLOAD", the request embed of PayloadV1
+	Request          string // "REQUEST", the request embed of PayloadV2
+	Response         string // "RESPONSE"
+	Message          string // "MESSAGE", numbered as "MESSAGE (2/3)" when split
+	MessagePreview   string // "MESSAGE (PREVIEW)"
+	ValidationErrors string // "VALIDATION ERRORS"
+	ErrorDetail      string // "ERROR DETAIL"
+	StackTrace       string // "STACK TRACE"
+	System           string // "SYSTEM"
+
+	// Field names
+	Error       string // "Error"
+	Caller      string // "Caller"
+	TraceID     string // "Trace ID"
+	SpanID      string // "Span ID"
+	Method      string // "Method"
+	URL         string // "URL"
+	Query       string // "Query"
+	Body        string // "Body"
+	BodySummary string // "Body (summary)"
+	BodyBinary  string // "Body (binary)"
+	Headers     string // "Headers"
+	ClientIP    string // "Client IP"
+	UserAgent   string // "User Agent"
+	Referer     string // "Referer"
+	PathParams  string // "Path Params"
+	Curl        string // "cURL"
+	Status      string // "Status"
+	Latency     string // "Latency"
+	Code        string // "Code", of gRPC calls
+	Peer        string // "Peer"
+	Metadata    string // "Metadata"
+	GrpcMessage string // "Message", the gRPC request message
+	GoVersion   string // "Go"
+	Platform    string // "Platform"
+	Goroutines  string // "Goroutines"
+	HeapInUse   string // "Heap In Use"
+	Uptime      string // "Uptime"
+
+	// Keys of the multipart body summary
+	FormFields    string // "form_fields"
+	UploadedFiles string // "uploaded_files"
+	FileName      string // "nama"
+	FileSize      string // "ukuran"
+}
+
+// DefaultLabels are the labels used without WithLabels
+var DefaultLabels = Labels{
+	RequestPayload:   "REQUEST PAYLOAD",
+	Request:          "REQUEST",
+	Response:         "RESPONSE",
+	Message:          "MESSAGE",
+	MessagePreview:   "MESSAGE (PREVIEW)",
+	ValidationErrors: "VALIDATION ERRORS",
+	ErrorDetail:      "ERROR DETAIL",
+	StackTrace:       "STACK TRACE",
+	System:           "SYSTEM",
+
+	Error:       "Error",
+	Caller:      "Caller",
+	TraceID:     "Trace ID",
+	SpanID:      "Span ID",
+	Method:      "Method",
+	URL:         "URL",
+	Query:       "Query",
+	Body:        "Body",
+	BodySummary: "Body (summary)",
+	BodyBinary:  "Body (binary)",
+	Headers:     "Headers",
+	ClientIP:    "Client IP",
+	UserAgent:   "User Agent",
+	Referer:     "Referer",
+	PathParams:  "Path Params",
+	Curl:        "cURL",
+	Status:      "Status",
+	Latency:     "Latency",
+	Code:        "Code",
+	Peer:        "Peer",
+	Metadata:    "Metadata",
+	GrpcMessage: "Message",
+	GoVersion:   "Go",
+	Platform:    "Platform",
+	Goroutines:  "Goroutines",
+	HeapInUse:   "Heap In Use",
+	Uptime:      "Uptime",
+
+	FormFields:    "form_fields",
+	UploadedFiles: "uploaded_files",
+	FileName:      "nama",
+	FileSize:      "ukuran",
+}
+
+// EnglishLabels are DefaultLabels with English keys for uploaded files ("name", "size")
+var EnglishLabels = func() Labels {
+	l := DefaultLabels
+	l.FileName, l.FileSize = "name", "size"
+	return l
+}()
+
+// WithLabels replaces the embed titles and field names of the default formatter, e.g. to
+// translate them. Empty labels keep their default. Options referring to rendered field
+// names, such as WithInlineFields, use the replaced names.
+//
+//	labels := discordrus.EnglishLabels
+//	labels.Message, labels.Body = "PESAN", "Isi"
+//	discordrus.WithLabels(labels)
+func WithLabels(l Labels) Option {
+	return func(h *Hook) {
+		merged := l.withDefaults()
+		h.labels = &merged
+	}
+}
+
+// withDefaults returns the labels with every empty label set to its default
+func (l Labels) withDefaults() Labels {
+	v := reflect.ValueOf(&l).Elem()
+	defaults := reflect.ValueOf(DefaultLabels)
+	for i := 0; i < v.NumField(); i++ {
+		if v.Field(i).String() == "" {
+			v.Field(i).SetString(defaults.Field(i).String())
+		}
+	}
+	return l
+}
+
+// labelSet returns the labels of the hook, DefaultLabels without WithLabels
+func (h *Hook) labelSet() *Labels {
+	if h.labels != nil {
+		return h.labels
+	}
+	return &DefaultLabels
+}
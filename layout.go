@@ -0,0 +1,44 @@
+package discordrus
+
+// WithInlineFields shows the given embed fields side by side (inline true) or on their
+// own row (inline false). Names are entry field keys or the names of rendered fields,
+// e.g. a row of request details with the body below:
+//
+//	discordrus.WithInlineFields(true, "Method", "Status", "Latency", "duration"),
+//	discordrus.WithInlineFields(false, "Body"),
+func WithInlineFields(inline bool, names ...string) Option {
+	return func(h *Hook) {
+		if h.inlineFields == nil {
+			h.inlineFields = make(map[string]bool, len(names))
+		}
+		for _, name := range names {
+			h.inlineFields[name] = inline
+		}
+	}
+}
+
+// WithDefaultInline sets the layout of every field without its own setting, see
+// WithInlineFields. By default, compact fields such as the well-known fields are inline
+// and the others stacked.
+func WithDefaultInline(inline bool) Option {
+	return func(h *Hook) {
+		h.defaultInline = &inline
+	}
+}
+
+// applyLayout sets the inline flag of the payload's fields according to the layout options
+func (h *Hook) applyLayout(payload *WebhookPayload) {
+	if len(h.inlineFields) == 0 && h.defaultInline == nil {
+		return
+	}
+	for i := range payload.Embeds {
+		for j := range payload.Embeds[i].Fields {
+			f := &payload.Embeds[i].Fields[j]
+			if inline, ok := h.inlineFields[f.Name]; ok {
+				f.Inline = inline
+			} else if h.defaultInline != nil {
+				f.Inline = *h.defaultInline
+			}
+		}
+	}
+}
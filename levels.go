@@ -0,0 +1,156 @@
+package discordrus
+
+import (
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/sirupsen/logrus"
+)
+
+// FORCE_SEND_FIELD_KEY sends the entry to Discord even if the hook does not process its
+// level, when set to true, e.g. for a one-off notification logged at Info level:
+//
+//	logger.WithField(discordrus.FORCE_SEND_FIELD_KEY, true).Info("deploy completed")
+//
+// The logger's own level still has to let the entry through.
+const FORCE_SEND_FIELD_KEY = "force_send"
+
+// ForceSend are the fields that force an entry to be sent, see FORCE_SEND_FIELD_KEY:
+//
+//	logger.WithFields(discordrus.ForceSend).Info("deploy completed")
+var ForceSend = logrus.Fields{FORCE_SEND_FIELD_KEY: true}
+
+// SKIP_FIELD_KEY keeps the entry out of Discord when set to true, even if the hook
+// processes its level, e.g. for expected errors that should not alert the team.
+// It takes precedence over FORCE_SEND_FIELD_KEY.
+const SKIP_FIELD_KEY = "skip_discord"
+
+// Skip are the fields that keep an entry out of Discord, see SKIP_FIELD_KEY:
+//
+//	logger.WithFields(discordrus.Skip).WithError(err).Error("card declined")
+var Skip = logrus.Fields{SKIP_FIELD_KEY: true}
+
+// WithoutDiscord returns an entry that is logged as usual but not sent to Discord,
+// see SKIP_FIELD_KEY:
+//
+//	discordrus.WithoutDiscord(logger).WithError(err).Error("card declined")
+func WithoutDiscord(logger logrus.FieldLogger) *logrus.Entry {
+	return logger.WithField(SKIP_FIELD_KEY, true)
+}
+
+var (
+	// AllLevels contains every logrus level, from Panic to Trace
+	AllLevels = append([]logrus.Level(nil), logrus.AllLevels...)
+
+	// CriticalLevels contains the Panic, Fatal and Error levels
+	CriticalLevels = []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
+
+	// DefaultLevels are the levels processed when a hook is created without levels:
+	// Panic, Fatal, Error and Warn
+	DefaultLevels = []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel, logrus.WarnLevel}
+)
+
+// LevelsFromString parses a level specification as used in flags or environment variables
+// It accepts a comma-separated list of level names ("error,warn"), a level followed by "+"
+// for that level and every more severe one ("warn+"), and "all" or "*".
+func LevelsFromString(spec string) ([]logrus.Level, error) {
+	var levels []logrus.Level
+	seen := make(map[logrus.Level]bool)
+	add := func(l logrus.Level) {
+		if !seen[l] {
+			seen[l] = true
+			levels = append(levels, l)
+		}
+	}
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		switch part {
+		case "":
+			continue
+		case "all", "*":
+			for _, l := range AllLevels {
+				add(l)
+			}
+			continue
+		}
+
+		orMoreSevere := strings.HasSuffix(part, "+")
+		l, err := logrus.ParseLevel(strings.TrimSuffix(part, "+"))
+		if err != nil {
+			return nil, eris.Wrapf(err, "invalid level specification %q", spec)
+		}
+		if !orMoreSevere {
+			add(l)
+			continue
+		}
+		for _, more := range levelsAtLeast(l) {
+			add(more)
+		}
+	}
+
+	if len(levels) == 0 {
+		return nil, eris.Errorf("invalid level specification %q: no levels", spec)
+	}
+	return levels, nil
+}
+
+// SetLevels changes the levels the hook processes, e.g. to send Info entries to Discord
+// during an incident without a restart. It is safe to call while the hook is registered
+// and used; the logger's own level still has to let the entries through. No levels
+// restores DefaultLevels.
+func (h *Hook) SetLevels(levels []logrus.Level) {
+	if len(levels) == 0 {
+		levels = DefaultLevels
+	}
+	var mask uint32
+	for _, l := range levels {
+		mask |= 1 << l
+	}
+
+	h.levelsMu.Lock()
+	defer h.levelsMu.Unlock()
+	h.lvl = append([]logrus.Level(nil), levels...)
+	h.levelMask.Store(mask)
+}
+
+// SetMinLevel makes the hook process the level and every more severe one, see SetLevels
+func (h *Hook) SetMinLevel(min logrus.Level) {
+	h.SetLevels(levelsAtLeast(min))
+}
+
+// EnabledLevels returns the levels the hook currently processes
+func (h *Hook) EnabledLevels() []logrus.Level {
+	h.levelsMu.Lock()
+	defer h.levelsMu.Unlock()
+	return append([]logrus.Level(nil), h.lvl...)
+}
+
+// levelEnabled reports whether entries of the level are processed
+func (h *Hook) levelEnabled(l logrus.Level) bool {
+	return l <= logrus.TraceLevel && h.levelMask.Load()&(1<<l) != 0
+}
+
+// skipped reports whether the entry is kept out of Discord, see SKIP_FIELD_KEY
+func skipped(entry *logrus.Entry) bool {
+	skip, _ := entry.Data[SKIP_FIELD_KEY].(bool)
+	return skip
+}
+
+// forceSend reports whether the entry is sent regardless of its level, see FORCE_SEND_FIELD_KEY
+func forceSend(entry *logrus.Entry) bool {
+	force, _ := entry.Data[FORCE_SEND_FIELD_KEY].(bool)
+	return force
+}
+
+// levelsAtLeast returns min and every more severe level, from Panic down to min
+func levelsAtLeast(min logrus.Level) []logrus.Level {
+	var levels []logrus.Level
+	// Level logrus yang lebih parah memiliki nilai lebih kecil
+	for _, l := range AllLevels {
+		if l <= min {
+			levels = append(levels, l)
+		}
+	}
+	return levels
+}
@@ -0,0 +1,137 @@
+package discordrus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"github.com/sirupsen/logrus"
+)
+
+// fatalFlushTimeout bounds how long FatalAndFlush waits for delivery before exiting
+const fatalFlushTimeout = 10 * time.Second
+
+// flushPollInterval is how often Flush sends the messages held back by coalescing,
+// batching and deduplication again while it waits, e.g. entries still in the queue
+// when it was called
+const flushPollInterval = 20 * time.Millisecond
+
+// closedChan is returned by inflight.idleChan when nothing is in flight
+var closedChan = func() chan struct{} {
+	c := make(chan struct{})
+	close(c)
+	return c
+}()
+
+// fatalOnce makes sure concurrent FatalAndFlush calls send a single alert
+var fatalOnce sync.Once
+
+// inflight tracks deliveries that have not finished yet
+// The zero value is ready to use
+type inflight struct {
+	mu   sync.Mutex
+	n    int
+	idle chan struct{}
+}
+
+func (f *inflight) add() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.n == 0 {
+		f.idle = make(chan struct{})
+	}
+	f.n++
+}
+
+func (f *inflight) done() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.n--
+	if f.n == 0 {
+		close(f.idle)
+	}
+}
+
+// idleChan returns a channel that is closed once no delivery is in flight
+func (f *inflight) idleChan() <-chan struct{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.n == 0 {
+		return closedChan
+	}
+	return f.idle
+}
+
+// Flush blocks until all pending deliveries have finished or ctx is done
+// Coalesced and batched entries, the counters of deduplicated entries and drop reports are sent right away. Entries fired while Flush is waiting are waited for as well.
+func (h *Hook) Flush(ctx context.Context) error {
+	ticker := time.NewTicker(flushPollInterval)
+	defer ticker.Stop()
+	for {
+		// Kirim burst dan batch yang masih menunggu window tanpa menunggu timer. Diulang
+		// selama menunggu, karena job yang masih di queue bisa menambah burst atau batch baru.
+		if h.coalesce != nil {
+			h.coalesce.flushAll()
+		}
+		if h.batch != nil {
+			h.batch.flushAll()
+		}
+		if h.dedup != nil {
+			h.dedup.flushAll()
+		}
+		h.sendDropReports()
+
+		select {
+		case <-h.pending.idleChan():
+			return nil
+		case <-ctx.Done():
+			return eris.Wrap(ctx.Err(), "discord hook flush interrupted")
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops accepting new entries and waits until all pending deliveries have finished
+// Entries fired after Close are rejected with an error. The health probe, the replay of
+// the persistent queue and the delivery workers are stopped and the last digest and drop
+// report are sent.
+func (h *Hook) Close() error {
+	h.closed.Store(true)
+	h.stopProbe()
+	h.stopReplay()
+	h.stopDigest()
+	err := h.Flush(context.Background())
+	h.pool.shutdown()
+	// Setelah shutdown tidak ada entry yang bisa di-drop lagi, jadi sisa laporan dikirim di sini
+	h.stopDropReports()
+	return err
+}
+
+// FatalAndFlush logs msg with fields at fatal level, waits up to 10 seconds until the
+// discordrus hooks of the logger have delivered it and then exits through logger.Exit(1).
+// Unlike logger.Fatal, the alert is not lost when the hook delivers asynchronously:
+//
+//	if err := run(); err != nil {
+//		discordrus.FatalAndFlush(logger, "worker stopped", logrus.Fields{logrus.ErrorKey: err})
+//	}
+//
+// When called concurrently, only the first call logs; the others wait for it and exit.
+func FatalAndFlush(logger *logrus.Logger, msg string, fields logrus.Fields) {
+	fatalOnce.Do(func() {
+		// Log langsung lewat Log, karena logger.Fatal langsung exit sebelum hook selesai mengirim
+		logger.WithFields(fields).Log(logrus.FatalLevel, msg)
+
+		ctx, cancel := context.WithTimeout(context.Background(), fatalFlushTimeout)
+		defer cancel()
+		for _, hook := range logger.Hooks[logrus.FatalLevel] {
+			if h, ok := hook.(*Hook); ok {
+				h.Flush(ctx)
+			}
+		}
+	})
+	logger.Exit(1)
+}
@@ -0,0 +1,148 @@
+package discordrus
+
+import "strings"
+
+// EmbedLimits are the sizes the built payload is truncated to before it is sent,
+// so an oversized component (e.g. a long request body) does not make Discord reject
+// the whole message with HTTP 400. Zero values use the value of DefaultEmbedLimits.
+type EmbedLimits struct {
+	Content     int    // Characters of the message content
+	Title       int    // Characters of an embed title
+	Description int    // Characters of an embed description
+	FieldName   int    // Characters of a field name
+	FieldValue  int    // Characters of a field value
+	FooterText  int    // Characters of an embed footer
+	Fields      int    // Fields per embed
+	Embeds      int    // Embeds per message
+	Total       int    // Characters across all embeds of a message
+	Ellipsis    string // Marker appended to truncated text
+}
+
+// DefaultEmbedLimits are Discord's documented limits
+var DefaultEmbedLimits = EmbedLimits{
+	Content:     2000,
+	Title:       256,
+	Description: 4096,
+	FieldName:   256,
+	FieldValue:  1024,
+	FooterText:  2048,
+	Fields:      25,
+	Embeds:      maxEmbedsPerMessage,
+	Total:       maxEmbedCharsPerMessage,
+	Ellipsis:    "…",
+}
+
+// WithEmbedLimits sets the limits the payload is truncated to, e.g. lower ones
+// to keep alerts short
+func WithEmbedLimits(l EmbedLimits) Option {
+	return func(h *Hook) {
+		h.limits = l
+	}
+}
+
+// withDefaults fills the unset limits with the default ones
+func (l EmbedLimits) withDefaults() EmbedLimits {
+	d := DefaultEmbedLimits
+	for _, v := range []struct{ dst, def *int }{
+		{&l.Content, &d.Content}, {&l.Title, &d.Title}, {&l.Description, &d.Description},
+		{&l.FieldName, &d.FieldName}, {&l.FieldValue, &d.FieldValue}, {&l.FooterText, &d.FooterText},
+		{&l.Fields, &d.Fields}, {&l.Embeds, &d.Embeds}, {&l.Total, &d.Total},
+	} {
+		if *v.dst <= 0 {
+			*v.dst = *v.def
+		}
+	}
+	if l.Ellipsis == "" {
+		l.Ellipsis = d.Ellipsis
+	}
+	return l
+}
+
+// apply truncates every component of the payload to the limits
+func (l EmbedLimits) apply(p *WebhookPayload) {
+	l = l.withDefaults()
+	p.Content = l.truncate(p.Content, l.Content)
+	if len(p.Embeds) > l.Embeds {
+		p.Embeds = p.Embeds[:l.Embeds]
+	}
+	for i := range p.Embeds {
+		e := &p.Embeds[i]
+		e.Title = l.truncate(e.Title, l.Title)
+		e.Description = l.truncate(e.Description, l.Description)
+		if e.Footer != nil {
+			e.Footer.Text = l.truncate(e.Footer.Text, l.FooterText)
+		}
+		if len(e.Fields) > l.Fields {
+			e.Fields = e.Fields[:l.Fields]
+		}
+		for j := range e.Fields {
+			e.Fields[j].Name = l.truncate(e.Fields[j].Name, l.FieldName)
+			e.Fields[j].Value = l.truncate(e.Fields[j].Value, l.FieldValue)
+		}
+	}
+	l.fitTotal(p)
+}
+
+// fitTotal shortens the longest texts until all embeds fit into the total limit,
+// dropping the last embeds if that is not enough
+func (l EmbedLimits) fitTotal(p *WebhookPayload) {
+	for excess := embedChars(p.Embeds) - l.Total; excess > 0; excess = embedChars(p.Embeds) - l.Total {
+		text := longestText(p.Embeds)
+		if text == nil || runeLen(*text) <= minTruncatedLength {
+			// Tidak ada teks yang masih bisa dipotong, buang embed terakhir
+			if len(p.Embeds) <= 1 {
+				return
+			}
+			p.Embeds = p.Embeds[:len(p.Embeds)-1]
+			continue
+		}
+		*text = l.truncate(*text, max(runeLen(*text)-excess, minTruncatedLength))
+	}
+}
+
+// minTruncatedLength is the number of characters a text keeps when the payload is shortened
+const minTruncatedLength = 100
+
+// longestText returns the longest description or field value of the embeds
+func longestText(embeds []Embed) *string {
+	var longest *string
+	for i := range embeds {
+		candidates := []*string{&embeds[i].Description}
+		for j := range embeds[i].Fields {
+			candidates = append(candidates, &embeds[i].Fields[j].Value)
+		}
+		for _, c := range candidates {
+			if longest == nil || runeLen(*c) > runeLen(*longest) {
+				longest = c
+			}
+		}
+	}
+	return longest
+}
+
+// truncate cuts s to at most n characters including the ellipsis. A text wrapped
+// in a code block keeps its closing fence.
+func (l EmbedLimits) truncate(s string, n int) string {
+	if runeLen(s) <= n {
+		return s
+	}
+	open, closing := "", ""
+	if strings.HasPrefix(s, "```") && strings.HasSuffix(s, "```") && len(s) >= 6 {
+		if strings.HasSuffix(s, " ```") {
+			open, closing, s = "```", " ```", s[3:len(s)-4]
+		} else {
+			open, closing, s = "```", "```", s[3:len(s)-3]
+		}
+	}
+	keep := n - runeLen(open) - runeLen(closing) - runeLen(l.Ellipsis)
+	if keep < 0 {
+		// Batas terlalu kecil untuk code block, potong tanpa fence
+		return string([]rune(open + s)[:max(n, 0)])
+	}
+	return open + string([]rune(s)[:keep]) + l.Ellipsis + closing
+}
+
+// runeLen returns the number of characters of s
+func runeLen(s string) int {
+	return len([]rune(s))
+}
@@ -0,0 +1,147 @@
+package discordrus
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/murbagus/discordrus/sender"
+	"github.com/sirupsen/logrus"
+)
+
+// attachment is a file uploaded together with the webhook payload
+// Its content comes either from Data or is streamed from source
+type attachment struct {
+	Name   string
+	Data   []byte
+	source *attachmentSource
+}
+
+// message is a fully built webhook message: the JSON payload and its files
+type message struct {
+	URL     string // Destination webhook
+	Payload []byte
+	Files   []attachment
+
+	// threadID posts the message into an existing thread (?thread_id=)
+	threadID string
+	// threadName is the thread created for the message, messages for different threads are never batched together
+	threadName string
+
+	// editID is the ID of a previously sent message that is edited instead of posting a new one
+	editID string
+	// wait asks Discord to return the created message (?wait=true)
+	wait bool
+	// progressKey groups the entries of a long-running operation, see WithProgressField
+	progressKey  string
+	progressDone bool
+	// progressSeq orders the updates of the operation, taken when the entry was fired
+	progressSeq uint64
+
+	// muteKey is muted for muteFor once the message was delivered, see MUTE_FIELD_KEY
+	muteKey string
+	muteFor time.Duration
+
+	// entry is the log entry the message was built from, reported with delivery errors
+	entry *logrus.Entry
+	// batched are the entries of a merged batch, see WithBatching
+	batched []*logrus.Entry
+	// dedup is the deduplication window started by the message, see WithDeduplication
+	dedup *dedupRecord
+	// replayed marks a message delivered from the persistent queue, see WithPersistentQueue
+	replayed bool
+}
+
+// outgoing converts the message for the sender
+// Streamed attachments are copied up to maxAttachmentSize bytes.
+func (m *message) outgoing(maxAttachmentSize int64) *sender.Message {
+	out := &sender.Message{
+		WebhookURL: m.URL,
+		Payload:    m.Payload,
+		ThreadID:   m.threadID,
+		EditID:     m.editID,
+		Wait:       m.wait,
+	}
+	for _, f := range m.Files {
+		file := sender.File{Name: f.Name, Data: f.Data}
+		if src := f.source; src != nil {
+			file.Open = func() (io.Reader, error) {
+				r, release, err := src.get()
+				if err != nil {
+					return nil, err
+				}
+				return &cappedReader{r: r, limit: maxAttachmentSize, remaining: maxAttachmentSize, release: release}, nil
+			}
+		}
+		out.Files = append(out.Files, file)
+	}
+	return out
+}
+
+// close releases the caller provided attachment readers
+func (m *message) close() {
+	m.dedup.release()
+	for _, f := range m.Files {
+		if f.source != nil {
+			f.source.close()
+		}
+	}
+}
+
+// deliver exports the message when export mode is enabled, otherwise posts it to Discord
+// (and reads it back when delivery verification is enabled). Stale messages are dropped
+// or summarized first, see WithMaxAlertAge.
+func (h *Hook) deliver(m *message) error {
+	defer m.close()
+
+	if h.dropStale(m) {
+		return nil
+	}
+	if h.exportDir != "" {
+		return exportBundle(h.exportDir, m, h.attachmentLimit())
+	}
+	if m.progressKey != "" {
+		return h.sendProgress(m)
+	}
+	if h.verifyDelivery {
+		return h.sendVerified(m)
+	}
+	_, err := h.send(m)
+	return err
+}
+
+// send posts the message to the Discord webhook and records the outcome in the stats
+// It returns the body of Discord's response (the created message when wait is set)
+// OnDelivered, if set, is called with the created message, failed messages are written to FallbackWriter
+// and the persistent queue. Replayed messages were written to FallbackWriter when they were queued.
+func (h *Hook) send(m *message) ([]byte, error) {
+	if h.OnDelivered != nil || m.dedup != nil {
+		// Discord hanya mengembalikan ID pesan dengan wait=true
+		m.wait = true
+	}
+	out := m.outgoing(h.attachmentLimit())
+	if h.componentsV2 && h.customSender == nil {
+		h.componentsMessage(out)
+	}
+	res, err := h.delivery().Send(context.Background(), out)
+	if res.Attempts > 0 {
+		h.destinationFor(m.URL).record(err, res.Latency)
+		h.metrics.record(res, err)
+	}
+	if err != nil && h.FallbackWriter != nil && !m.replayed {
+		h.writeFallback(m, err)
+	}
+	if err != nil && h.persist != nil {
+		h.persistMessage(m, err)
+	}
+	if err == nil && h.persist != nil && !m.replayed {
+		h.persist.wakeReplay()
+	}
+	if err == nil && m.dedup != nil {
+		m.dedup.delivered(m, res.Body)
+	}
+	if err == nil && h.OnDelivered != nil {
+		h.reportDelivered(m, res.Body, res.Attempts, res.Latency)
+	}
+	return res.Body, err
+}
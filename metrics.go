@@ -0,0 +1,88 @@
+package discordrus
+
+import (
+	"sync"
+	"time"
+
+	"github.com/murbagus/discordrus/sender"
+)
+
+// LatencyBuckets are the upper bounds of the delivery latency histogram, see Metrics
+var LatencyBuckets = []time.Duration{
+	50 * time.Millisecond, 100 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond,
+	time.Second, 2500 * time.Millisecond, 5 * time.Second, 10 * time.Second,
+}
+
+// Metrics holds the delivery counters of a hook across all destinations, e.g. to alert
+// when Discord delivery is failing. The discordprom package exports them to Prometheus.
+type Metrics struct {
+	Sent        uint64 // Messages delivered
+	Failed      uint64 // Messages that could not be delivered
+	Retried     uint64 // Requests repeated after a transient failure or rate limit
+	RateLimited uint64 // Requests answered with HTTP 429
+	Dropped     uint64 // Entries dropped because the queue was full, see DroppedCount
+
+	// Latency of delivered messages: LatencyCounts[i] is the number of deliveries that took
+	// at most LatencyBuckets[i] (cumulative), LatencyCount includes slower ones as well
+	LatencyCounts []uint64
+	LatencyCount  uint64
+	LatencySum    time.Duration
+}
+
+// metrics collects the delivery counters reported by Metrics
+type metrics struct {
+	mu            sync.Mutex
+	sent          uint64
+	failed        uint64
+	retried       uint64
+	rateLimited   uint64
+	latencyCounts []uint64
+	latencyCount  uint64
+	latencySum    time.Duration
+}
+
+// record counts the outcome of a delivery
+func (m *metrics) record(res sender.Result, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if res.Attempts > 1 {
+		m.retried += uint64(res.Attempts - 1)
+	}
+	m.rateLimited += uint64(res.RateLimited)
+	if err != nil {
+		m.failed++
+		return
+	}
+	m.sent++
+
+	if m.latencyCounts == nil {
+		m.latencyCounts = make([]uint64, len(LatencyBuckets))
+	}
+	for i, bound := range LatencyBuckets {
+		if res.Latency <= bound {
+			m.latencyCounts[i]++
+		}
+	}
+	m.latencyCount++
+	m.latencySum += res.Latency
+}
+
+// Metrics returns the delivery counters of the hook since it was created
+func (h *Hook) Metrics() Metrics {
+	h.metrics.mu.Lock()
+	m := Metrics{
+		Sent:          h.metrics.sent,
+		Failed:        h.metrics.failed,
+		Retried:       h.metrics.retried,
+		RateLimited:   h.metrics.rateLimited,
+		LatencyCounts: make([]uint64, len(LatencyBuckets)),
+		LatencyCount:  h.metrics.latencyCount,
+		LatencySum:    h.metrics.latencySum,
+	}
+	copy(m.LatencyCounts, h.metrics.latencyCounts)
+	h.metrics.mu.Unlock()
+
+	m.Dropped = h.DroppedCount()
+	return m
+}
@@ -0,0 +1,53 @@
+package discordrus
+
+import (
+	"net/http"
+
+	"github.com/rotisserie/eris"
+	"github.com/sirupsen/logrus"
+)
+
+// Middleware snapshots every incoming request, including up to 1 MB of its body, and stores
+// it in the request context. Entries logged with that context carry the request payload
+// without passing REQUEST_FIELD_KEY:
+//
+//	http.Handle("/", discordrus.Middleware(logger)(handler))
+//
+//	func handler(w http.ResponseWriter, r *http.Request) {
+//		logger.WithContext(r.Context()).WithError(err).Error("failed to create order")
+//	}
+//
+// The handler still reads the complete body. Panics are logged at error level with the
+// request and then re-panicked, so the server's own recovery still applies.
+func Middleware(logger *logrus.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = CaptureRequest(r)
+			defer func() {
+				if v := recover(); v != nil {
+					if v != http.ErrAbortHandler {
+						logger.WithContext(r.Context()).WithError(PanicError(v)).Errorf("panic serving %s %s", r.Method, r.URL.Path)
+					}
+					panic(v)
+				}
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CaptureRequest snapshots the request, including up to 1 MB of its body, and returns it
+// with the snapshot stored in its context, see Middleware. The returned request still
+// reads the complete body. It is the building block for framework integrations.
+func CaptureRequest(r *http.Request) *http.Request {
+	return r.WithContext(ContextWithRequest(r.Context(), LoggerHttpRequestPayload{Request: r}))
+}
+
+// PanicError converts a recovered panic value into an error carrying the stack of the
+// panic, so it is rendered by WithStackTrace
+func PanicError(v any) error {
+	if err, ok := v.(error); ok {
+		return eris.Wrap(err, "panic")
+	}
+	return eris.Errorf("panic: %v", v)
+}
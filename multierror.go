@@ -0,0 +1,48 @@
+package discordrus
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxJoinedErrorsShown is the number of components of a multi-error shown inline
+const maxJoinedErrorsShown = 5
+
+// joinedErrors returns the components of a multi-error (errors.Join and other
+// errors implementing Unwrap() []error), flattening nested multi-errors
+func joinedErrors(err error) []error {
+	multi, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		return nil
+	}
+
+	var errs []error
+	for _, e := range multi.Unwrap() {
+		if e == nil {
+			continue
+		}
+		if nested := joinedErrors(e); nested != nil {
+			errs = append(errs, nested...)
+		} else {
+			errs = append(errs, e)
+		}
+	}
+	return errs
+}
+
+// summarizeJoinedErrors summarizes a multi-error with more than maxJoinedErrorsShown
+// components as "N errors: first 5 shown…" and returns the full list for errors.txt
+func summarizeJoinedErrors(err error) (summary, full string, ok bool) {
+	errs := joinedErrors(err)
+	if len(errs) <= maxJoinedErrorsShown {
+		return "", "", false
+	}
+
+	lines := make([]string, len(errs))
+	for i, e := range errs {
+		lines[i] = fmt.Sprintf("%d. %s", i+1, e.Error())
+	}
+	summary = fmt.Sprintf("%d errors: first %d shown…\n", len(errs), maxJoinedErrorsShown) +
+		strings.Join(lines[:maxJoinedErrorsShown], "\n")
+	return previewText(summary, maxEmbedDescriptionLength), strings.Join(lines, "\n"), true
+}
@@ -0,0 +1,125 @@
+package discordrus
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// MUTE_FIELD_KEY mutes the fingerprint of the entry for the given time.Duration (or a
+// duration string such as "1h") after the entry has been delivered, e.g. for a known
+// error that keeps failing during remediation. Nothing is muted when the delivery fails.
+const MUTE_FIELD_KEY = "mute_for"
+
+// maxMutes bounds the muted fingerprints, the ones expiring first are forgotten first
+const maxMutes = 10000
+
+// Fingerprint identifies entries with the same level, message and error. It is shown in
+// the footer of every message, so responders can pass it to Hook.Mute.
+func Fingerprint(entry *logrus.Entry) string {
+	errText := ""
+	if err, ok := entry.Data[logrus.ErrorKey].(error); ok && err != nil && !nilError(entry) {
+		errText = err.Error()
+	}
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%d\x00%s\x00%s", entry.Level, entry.Message, errText)))
+	return hex.EncodeToString(sum[:6])
+}
+
+// fingerprint identifies the entry for mutes, digests, burst coalescing and the footer:
+// the key of WithDeduplication when one was given, Fingerprint otherwise
+func (h *Hook) fingerprint(entry *logrus.Entry) string {
+	if h.dedup != nil {
+		return h.dedup.key(entry)
+	}
+	return Fingerprint(entry)
+}
+
+// mutes holds the fingerprints that are not delivered until a point in time
+type mutes struct {
+	mu    sync.Mutex
+	until map[string]time.Time
+}
+
+// Mute stops delivering entries with the fingerprint for d, see Fingerprint
+// Muting a fingerprint again replaces the previous duration. At most 10000 fingerprints
+// are muted at a time; beyond that, the mute expiring first is lifted early.
+func (h *Hook) Mute(fingerprint string, d time.Duration) {
+	h.mutes.mu.Lock()
+	defer h.mutes.mu.Unlock()
+
+	now := time.Now()
+	if h.mutes.until == nil {
+		h.mutes.until = make(map[string]time.Time)
+	}
+	if _, ok := h.mutes.until[fingerprint]; !ok {
+		h.mutes.evict(now)
+	}
+	h.mutes.until[fingerprint] = now.Add(d)
+}
+
+// evict removes expired mutes and, when maxMutes is reached, the one expiring first
+// The caller holds m.mu.
+func (m *mutes) evict(now time.Time) {
+	var firstKey string
+	var first time.Time
+	for key, until := range m.until {
+		if now.After(until) {
+			delete(m.until, key)
+			continue
+		}
+		if firstKey == "" || until.Before(first) {
+			firstKey, first = key, until
+		}
+	}
+	if firstKey != "" && len(m.until) >= maxMutes {
+		delete(m.until, firstKey)
+	}
+}
+
+// muteDelivered mutes the fingerprint of a delivered message whose entry asked for it,
+// see MUTE_FIELD_KEY
+func (h *Hook) muteDelivered(m *message) {
+	if m.muteFor > 0 {
+		h.Mute(m.muteKey, m.muteFor)
+	}
+}
+
+// Unmute delivers entries with the fingerprint again
+func (h *Hook) Unmute(fingerprint string) {
+	h.mutes.mu.Lock()
+	defer h.mutes.mu.Unlock()
+
+	delete(h.mutes.until, fingerprint)
+}
+
+// muted reports whether entries with the fingerprint are currently muted
+func (h *Hook) muted(fingerprint string) bool {
+	h.mutes.mu.Lock()
+	defer h.mutes.mu.Unlock()
+
+	until, ok := h.mutes.until[fingerprint]
+	if !ok {
+		return false
+	}
+	if time.Now().After(until) {
+		delete(h.mutes.until, fingerprint)
+		return false
+	}
+	return true
+}
+
+// muteFor returns the duration of the entry's MUTE_FIELD_KEY field, if any
+func muteFor(entry *logrus.Entry) (time.Duration, bool) {
+	switch v := entry.Data[MUTE_FIELD_KEY].(type) {
+	case time.Duration:
+		return v, v > 0
+	case string:
+		d, err := time.ParseDuration(v)
+		return d, err == nil && d > 0
+	}
+	return 0, false
+}
@@ -0,0 +1,60 @@
+package discordrus
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestMuteFieldOnlyAfterDelivery(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		wantMuted bool
+	}{
+		{"delivered", http.StatusNoContent, true},
+		{"rejected", http.StatusBadRequest, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			h := NewHook(srv.URL, WithSynchronous(), WithRetryPolicy(RetryPolicy{}))
+			entry := errorEntry(logrus.New(), "replica lagging")
+			entry.Data[MUTE_FIELD_KEY] = time.Hour
+			h.Fire(entry)
+
+			if got := h.muted(h.fingerprint(entry)); got != tt.wantMuted {
+				t.Errorf("muted = %v, want %v", got, tt.wantMuted)
+			}
+		})
+	}
+}
+
+func TestMutesAreEvicted(t *testing.T) {
+	h := NewHook("https://discord.com/api/webhooks/1/token")
+	for i := 0; i < 100; i++ {
+		h.Mute(fmt.Sprintf("expired-%d", i), -time.Second)
+	}
+	h.Mute("active", time.Hour)
+	if got := len(h.mutes.until); got != 1 {
+		t.Errorf("%d mutes remembered after expiry, want 1", got)
+	}
+
+	for i := 0; i < maxMutes+10; i++ {
+		h.Mute(fmt.Sprintf("fp-%d", i), time.Hour+time.Duration(i)*time.Second)
+	}
+	if got := len(h.mutes.until); got > maxMutes {
+		t.Errorf("%d mutes remembered, want at most %d", got, maxMutes)
+	}
+	if !h.muted(fmt.Sprintf("fp-%d", maxMutes+9)) {
+		t.Error("latest mute was evicted")
+	}
+}
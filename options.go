@@ -0,0 +1,96 @@
+package discordrus
+
+import (
+	"crypto/tls"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Option configures a Hook created with NewHook
+type Option func(*Hook)
+
+// WithLevels sets the log levels the hook will process
+func WithLevels(levels ...logrus.Level) Option {
+	return func(h *Hook) {
+		h.lvl = levels
+	}
+}
+
+// WithMinLevel makes the hook process the level and every more severe one, e.g.
+// WithMinLevel(logrus.WarnLevel) processes Panic, Fatal, Error and Warn. It replaces
+// the levels set with WithLevels, the option applied last wins.
+func WithMinLevel(min logrus.Level) Option {
+	return func(h *Hook) {
+		h.lvl = levelsAtLeast(min)
+	}
+}
+
+// WithUsername sets the display name of the webhook messages, see Hook.Username
+func WithUsername(username string) Option {
+	return func(h *Hook) {
+		h.Username = username
+	}
+}
+
+// WithAvatarURL sets the avatar of the webhook messages, see Hook.AvatarURL
+func WithAvatarURL(avatarURL string) Option {
+	return func(h *Hook) {
+		h.AvatarURL = avatarURL
+	}
+}
+
+// WithSynchronous makes Fire deliver entries before returning, see Hook.Async
+func WithSynchronous() Option {
+	return func(h *Hook) {
+		h.Async = false
+	}
+}
+
+// WithHTTPClient sets the client used to deliver webhook requests, see Hook.Client
+func WithHTTPClient(client *http.Client) Option {
+	return func(h *Hook) {
+		h.Client = client
+	}
+}
+
+// WithTimeout limits the time a single request to Discord may take, so a hung
+// webhook cannot block deliveries forever. It defaults to DefaultTimeout, 0 disables it.
+func WithTimeout(d time.Duration) Option {
+	return func(h *Hook) {
+		h.timeout = d
+	}
+}
+
+// WithClientCert adds a client certificate that is presented on every TLS
+// connection to the webhook endpoint (or to a forward proxy requiring mTLS)
+func WithClientCert(cert tls.Certificate) Option {
+	return func(h *Hook) {
+		t := h.ensureTransport()
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		}
+		t.TLSClientConfig.Certificates = append(t.TLSClientConfig.Certificates, cert)
+	}
+}
+
+// ensureTransport returns the hook's own transport, cloning the default one on first use
+// When http.DefaultTransport was replaced by a wrapper, a new transport is used instead.
+func (h *Hook) ensureTransport() *http.Transport {
+	if h.transport == nil {
+		if t, ok := http.DefaultTransport.(*http.Transport); ok {
+			h.transport = t.Clone()
+		} else {
+			h.transport = &http.Transport{Proxy: http.ProxyFromEnvironment, ForceAttemptHTTP2: true}
+		}
+	}
+	return h.transport
+}
+
+// WithSigningSecret enables HMAC-SHA256 signing of outgoing requests, see Hook.SigningSecret
+func WithSigningSecret(secret string) Option {
+	return func(h *Hook) {
+		h.SigningSecret = secret
+	}
+}
@@ -0,0 +1,39 @@
+package discordrus
+
+import "strconv"
+
+// PayloadVersion selects the embed structure built by the default formatter, see WithPayloadVersion
+type PayloadVersion int
+
+const (
+	// PayloadV1 is the original structure: a level embed with the error as description,
+	// an always present REQUEST PAYLOAD embed and the message in MESSAGE embeds
+	PayloadV1 PayloadVersion = 1
+
+	// PayloadV2 is the structured layout: the message is the description of the level
+	// embed with the error as its first field, and the REQUEST embed is only present
+	// when the entry carries a request
+	PayloadV2 PayloadVersion = 2
+)
+
+// String returns the version as "v1" or "v2"
+func (v PayloadVersion) String() string {
+	return "v" + strconv.Itoa(int(v.orDefault()))
+}
+
+// orDefault returns the version, PayloadV1 when unset or unknown
+func (v PayloadVersion) orDefault() PayloadVersion {
+	if v == PayloadV2 {
+		return PayloadV2
+	}
+	return PayloadV1
+}
+
+// WithPayloadVersion pins the embed structure of the default formatter (default: PayloadV1),
+// so tools parsing the embeds keep working when the package is upgraded. New layouts are
+// only used after opting in to a newer version.
+func WithPayloadVersion(v PayloadVersion) Option {
+	return func(h *Hook) {
+		h.payloadVersion = v
+	}
+}
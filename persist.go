@@ -0,0 +1,251 @@
+package discordrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/murbagus/discordrus/sender"
+	"github.com/rotisserie/eris"
+)
+
+const (
+	// maxPersistedQueueSize caps the size of the persistent queue file
+	maxPersistedQueueSize = 64 << 20
+
+	// persistRetryInterval is the time between replays while messages are queued on disk
+	persistRetryInterval = 30 * time.Second
+)
+
+// WithPersistentQueue stores messages that could not be delivered because Discord was
+// unreachable (network errors, HTTP 5xx, rate limits) in an append-only file at path and
+// delivers them later: when the hook is created, after the next successful delivery and
+// every 30 seconds while messages are queued. Messages Discord rejected for good, e.g. a
+// deleted webhook, are not queued. The file holds full webhook URLs and is created with
+// mode 0600; it may grow up to 64 MB, further messages are reported to OnError.
+//
+//	discordrus.WithPersistentQueue("/var/lib/myapp/discord-queue.jsonl")
+func WithPersistentQueue(path string) Option {
+	return func(h *Hook) {
+		if path != "" {
+			h.persist = &persistentQueue{
+				path: path,
+				wake: make(chan struct{}, 1),
+				stop: make(chan struct{}),
+				done: make(chan struct{}),
+			}
+		}
+	}
+}
+
+// persistentQueue is the file of undelivered messages, see WithPersistentQueue
+type persistentQueue struct {
+	path string
+
+	mu   sync.Mutex // menjaga append dan penulisan ulang file
+	size int64
+
+	wake     chan struct{}
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// persistedMessage is a message stored in the persistent queue, one JSON line each
+type persistedMessage struct {
+	Time       time.Time       `json:"time"`
+	WebhookURL string          `json:"webhook_url"`
+	ThreadID   string          `json:"thread_id,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+	Files      []persistedFile `json:"files,omitempty"`
+}
+
+// persistedFile is an attachment of a persisted message
+type persistedFile struct {
+	Name string `json:"name"`
+	Data []byte `json:"data"`
+}
+
+// startReplay delivers the queued messages in the background until the hook is closed
+func (h *Hook) startReplay() {
+	q := h.persist
+	if info, err := os.Stat(q.path); err == nil {
+		q.size = info.Size()
+	}
+	go func() {
+		defer close(q.done)
+		ticker := time.NewTicker(persistRetryInterval)
+		defer ticker.Stop()
+		for {
+			h.replayPersisted()
+			select {
+			case <-ticker.C:
+			case <-q.wake:
+			case <-q.stop:
+				return
+			}
+		}
+	}()
+}
+
+// stopReplay stops the replay and waits for a running one to finish
+func (h *Hook) stopReplay() {
+	if h.persist != nil {
+		h.persist.stopOnce.Do(func() { close(h.persist.stop) })
+		<-h.persist.done
+	}
+}
+
+// wakeReplay starts a replay if messages are queued, e.g. when Discord is reachable again
+func (q *persistentQueue) wakeReplay() {
+	q.mu.Lock()
+	queued := q.size > 0
+	q.mu.Unlock()
+	if queued {
+		select {
+		case q.wake <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// persistMessage appends a message that failed with a retryable error to the queue
+// Edits of sent messages and replayed messages are not queued.
+func (h *Hook) persistMessage(m *message, deliveryErr error) {
+	if m.editID != "" || m.replayed || !sender.Retryable(deliveryErr) {
+		return
+	}
+	record := persistedMessage{
+		Time:       time.Now().UTC(),
+		WebhookURL: m.URL,
+		ThreadID:   m.threadID,
+		Payload:    m.Payload,
+	}
+	for _, f := range m.Files {
+		data := f.Data
+		if f.source != nil {
+			r, release, err := f.source.get()
+			if err != nil {
+				h.reportError(eris.Wrapf(err, "failed to open attachment %s for the persistent queue", f.Name), m.entry)
+				return
+			}
+			var buf bytes.Buffer
+			err = copyCapped(&buf, r, h.attachmentLimit())
+			release()
+			if err != nil {
+				h.reportError(eris.Wrapf(err, "failed to read attachment %s for the persistent queue", f.Name), m.entry)
+				return
+			}
+			data = buf.Bytes()
+		}
+		record.Files = append(record.Files, persistedFile{Name: f.Name, Data: data})
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		h.reportError(eris.Wrap(err, "failed to encode persisted message"), m.entry)
+		return
+	}
+	line = append(line, '\n')
+
+	q := h.persist
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.size+int64(len(line)) > maxPersistedQueueSize {
+		h.reportError(eris.New("Discord persistent queue is full, message dropped"), m.entry)
+		return
+	}
+	f, err := os.OpenFile(q.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		h.reportError(eris.Wrap(err, "failed to open the persistent queue"), m.entry)
+		return
+	}
+	_, err = f.Write(line)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		h.reportError(eris.Wrap(err, "failed to write to the persistent queue"), m.entry)
+		return
+	}
+	q.size += int64(len(line))
+}
+
+// replayPersisted delivers the queued messages in order, stopping at the first message
+// that still cannot be delivered, and removes the delivered ones from the file
+func (h *Hook) replayPersisted() {
+	q := h.persist
+	q.mu.Lock()
+	data, err := os.ReadFile(q.path)
+	q.mu.Unlock()
+	if err != nil {
+		if !os.IsNotExist(err) {
+			h.reportError(eris.Wrap(err, "failed to read the persistent queue"), nil)
+		}
+		return
+	}
+	read := len(data)
+
+	lines := bytes.SplitAfter(data, []byte("\n"))
+	handled := 0
+	for _, line := range lines {
+		if len(bytes.TrimSpace(line)) == 0 {
+			handled++
+			continue
+		}
+		// Baris terakhir yang belum lengkap (mis. proses mati saat menulis) ditunggu
+		if line[len(line)-1] != '\n' {
+			break
+		}
+
+		var record persistedMessage
+		if err := json.Unmarshal(line, &record); err != nil {
+			h.reportError(eris.Wrap(err, "dropped an invalid persisted message"), nil)
+			handled++
+			continue
+		}
+		m := &message{URL: record.WebhookURL, Payload: record.Payload, threadID: record.ThreadID, replayed: true}
+		for _, f := range record.Files {
+			m.Files = append(m.Files, attachment{Name: f.Name, Data: f.Data})
+		}
+		if _, err := h.send(m); err != nil {
+			if sender.Retryable(err) {
+				break
+			}
+			h.reportError(eris.Wrap(err, "dropped a persisted message"), nil)
+		}
+		handled++
+	}
+	if handled == 0 {
+		return
+	}
+
+	// Pesan yang ditambahkan selama replay tetap disimpan di belakang sisa antrean
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	current, err := os.ReadFile(q.path)
+	if err != nil {
+		h.reportError(eris.Wrap(err, "failed to read the persistent queue"), nil)
+		return
+	}
+	var rest []byte
+	for _, line := range lines[handled:] {
+		rest = append(rest, line...)
+	}
+	if len(current) > read {
+		rest = append(rest, current[read:]...)
+	}
+
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, rest, 0o600); err != nil {
+		h.reportError(eris.Wrap(err, "failed to rewrite the persistent queue"), nil)
+		return
+	}
+	if err := os.Rename(tmp, q.path); err != nil {
+		os.Remove(tmp)
+		h.reportError(eris.Wrap(err, "failed to rewrite the persistent queue"), nil)
+		return
+	}
+	q.size = int64(len(rest))
+}
@@ -0,0 +1,95 @@
+package discordrus
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// lockedBuffer is a bytes.Buffer safe for concurrent writes
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *lockedBuffer) lines() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Count(b.buf.String(), "\n")
+}
+
+func TestPersistentQueueReplayAfterRestart(t *testing.T) {
+	var down atomic.Bool
+	down.Store(true)
+	var delivered atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		delivered.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	path := filepath.Join(t.TempDir(), "queue.jsonl")
+	fallback := &lockedBuffer{}
+	logger := logrus.New()
+
+	h := NewHook(srv.URL, WithSynchronous(), WithRetryPolicy(RetryPolicy{}),
+		WithPersistentQueue(path), WithFallbackWriter(fallback))
+	h.OnError = func(error, *logrus.Entry) {}
+	for _, msg := range []string{"first", "second"} {
+		if err := h.Fire(errorEntry(logger, msg)); err == nil {
+			t.Fatalf("Fire(%q) succeeded while Discord is down", msg)
+		}
+	}
+	// Replay yang gagal tidak boleh menulis pesan yang sama ke fallback lagi
+	h.replayPersisted()
+	h.replayPersisted()
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := fallback.lines(); got != 2 {
+		t.Errorf("fallback has %d lines, want one per failed message (2)", got)
+	}
+	if data, _ := os.ReadFile(path); strings.Count(string(data), "\n") != 2 {
+		t.Fatalf("persistent queue holds %q, want 2 messages", data)
+	}
+
+	// "Restart": hook baru dengan file yang sama mengirim antrean saat dibuat
+	down.Store(false)
+	restarted := NewHook(srv.URL, WithSynchronous(), WithPersistentQueue(path))
+	defer restarted.Close()
+	deadline := time.Now().Add(5 * time.Second)
+	for delivered.Load() < 2 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if got := delivered.Load(); got != 2 {
+		t.Fatalf("replayed %d messages after restart, want 2", got)
+	}
+	for time.Now().Before(deadline) {
+		if data, err := os.ReadFile(path); err == nil && len(data) == 0 {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("persistent queue was not emptied after replay")
+}
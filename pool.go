@@ -0,0 +1,173 @@
+package discordrus
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// DefaultWorkers is the number of goroutines delivering asynchronous entries
+	DefaultWorkers = 4
+	// DefaultQueueSize is the number of asynchronous entries waiting for a worker
+	DefaultQueueSize = 1024
+)
+
+// OverflowPolicy decides what happens to an asynchronous entry when the queue is full
+type OverflowPolicy int
+
+const (
+	// OverflowDropNewest rejects the fired entry, keeping the queued ones (the default)
+	OverflowDropNewest OverflowPolicy = iota
+	// OverflowDropOldest drops the longest waiting entry to make room for the fired one
+	OverflowDropOldest
+	// OverflowBlock makes Fire wait until the queue has room
+	OverflowBlock
+)
+
+// String returns the name of the overflow policy
+func (p OverflowPolicy) String() string {
+	switch p {
+	case OverflowDropNewest:
+		return "drop_newest"
+	case OverflowDropOldest:
+		return "drop_oldest"
+	case OverflowBlock:
+		return "block"
+	}
+	return "unknown"
+}
+
+// WithWorkerPool delivers asynchronous entries with the given number of workers from a
+// queue of queueSize entries (default DefaultWorkers and DefaultQueueSize), so an error
+// storm does not start thousands of goroutines. Dropped entries are reported to OnError
+// with ErrQueueFull.
+func WithWorkerPool(workers, queueSize int, policy OverflowPolicy) Option {
+	return func(h *Hook) {
+		h.pool.workers = workers
+		h.pool.size = queueSize
+		h.pool.policy = policy
+	}
+}
+
+// job is a queued asynchronous entry
+type job struct {
+	entry  *logrus.Entry
+	url    string
+	run    func()
+	cancel func() // called instead of run when the job is dropped
+}
+
+// workerPool runs queued jobs with a fixed number of goroutines
+// The zero value uses the default size and is started on first use.
+type workerPool struct {
+	workers int
+	size    int
+	policy  OverflowPolicy
+
+	startOnce sync.Once
+	jobs      chan job
+	stop      chan struct{}
+
+	mu      sync.RWMutex // enqueue memegang read lock, shutdown write lock
+	stopped bool
+}
+
+// start launches the workers
+func (p *workerPool) start() {
+	p.startOnce.Do(func() {
+		p.jobs = make(chan job, p.sizeOrDefault())
+		p.stop = make(chan struct{})
+		for i := 0; i < p.workersOrDefault(); i++ {
+			go p.work()
+		}
+	})
+}
+
+func (p *workerPool) workersOrDefault() int {
+	if p.workers <= 0 {
+		return DefaultWorkers
+	}
+	return p.workers
+}
+
+func (p *workerPool) sizeOrDefault() int {
+	if p.size <= 0 {
+		return DefaultQueueSize
+	}
+	return p.size
+}
+
+func (p *workerPool) work() {
+	for {
+		select {
+		case j := <-p.jobs:
+			j.run()
+		case <-p.stop:
+			// Job yang masih di queue tetap dijalankan agar pending selalu dilepas
+			for {
+				select {
+				case j := <-p.jobs:
+					j.run()
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// shutdown stops the workers once the queue is drained, see Hook.Close
+func (p *workerPool) shutdown() {
+	p.start()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.stopped {
+		p.stopped = true
+		close(p.stop)
+	}
+}
+
+// enqueue queues the job according to the overflow policy, passing jobs that are
+// dropped to dropped. Jobs enqueued after shutdown run on the calling goroutine.
+func (p *workerPool) enqueue(j job, dropped func(job)) {
+	p.start()
+	p.mu.RLock()
+	if p.stopped {
+		p.mu.RUnlock()
+		j.run()
+		return
+	}
+	defer p.mu.RUnlock()
+
+	switch p.policy {
+	case OverflowBlock:
+		select {
+		case p.jobs <- j:
+		case <-p.stop:
+			dropped(j)
+		}
+		return
+	case OverflowDropOldest:
+		for {
+			select {
+			case p.jobs <- j:
+				return
+			default:
+			}
+			// Buang entry terlama untuk memberi tempat entry baru
+			select {
+			case oldest := <-p.jobs:
+				dropped(oldest)
+			default:
+			}
+		}
+	}
+
+	select {
+	case p.jobs <- j:
+	default:
+		dropped(j)
+	}
+}
@@ -0,0 +1,122 @@
+package discordrus
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// countingServer is a Discord webhook stub counting the messages posted to it
+func countingServer(t *testing.T, delay time.Duration) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var posts atomic.Int64
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(io.Discard, r.Body)
+		time.Sleep(delay)
+		posts.Add(1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(srv.Close)
+	return srv, &posts
+}
+
+func errorEntry(logger *logrus.Logger, msg string) *logrus.Entry {
+	return &logrus.Entry{Logger: logger, Level: logrus.ErrorLevel, Time: time.Now(), Message: msg, Data: logrus.Fields{}}
+}
+
+func TestPoolDrainsOnClose(t *testing.T) {
+	srv, posts := countingServer(t, 10*time.Millisecond)
+	h := NewHook(srv.URL, WithWorkerPool(2, 64, OverflowBlock))
+	h.OnError = func(err error, _ *logrus.Entry) { t.Errorf("delivery failed: %v", err) }
+
+	logger := logrus.New()
+	const n = 20
+	for i := 0; i < n; i++ {
+		if err := h.Fire(errorEntry(logger, "queued")); err != nil {
+			t.Fatalf("Fire: %v", err)
+		}
+	}
+	if err := h.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if got := posts.Load(); got != n {
+		t.Errorf("posted %d messages after Close, want %d", got, n)
+	}
+	if err := h.Fire(errorEntry(logger, "late")); !errors.Is(err, ErrHookClosed) {
+		t.Errorf("Fire after Close = %v, want ErrHookClosed", err)
+	}
+}
+
+func TestPoolFireRacingClose(t *testing.T) {
+	srv, posts := countingServer(t, time.Millisecond)
+	h := NewHook(srv.URL, WithWorkerPool(2, 8, OverflowBlock))
+	h.OnError = func(err error, _ *logrus.Entry) { t.Errorf("delivery failed: %v", err) }
+
+	logger := logrus.New()
+	var accepted atomic.Int64
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 50; i++ {
+				err := h.Fire(errorEntry(logger, "racing"))
+				switch {
+				case err == nil:
+					accepted.Add(1)
+				case errors.Is(err, ErrHookClosed):
+					return
+				default:
+					t.Errorf("Fire: %v", err)
+					return
+				}
+			}
+		}()
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	closed := make(chan error, 1)
+	go func() { closed <- h.Close() }()
+	select {
+	case err := <-closed:
+		if err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("Close did not return")
+	}
+	wg.Wait()
+
+	// Entry yang lolos pengecekan closed harus tetap dikirim, Flush tidak boleh menggantung
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := h.Flush(ctx); err != nil {
+		t.Fatalf("Flush after Close: %v", err)
+	}
+	if got, want := posts.Load(), accepted.Load(); got != want {
+		t.Errorf("posted %d messages, want %d accepted entries", got, want)
+	}
+}
+
+func TestPoolEnqueueAfterShutdown(t *testing.T) {
+	for _, policy := range []OverflowPolicy{OverflowDropNewest, OverflowDropOldest, OverflowBlock} {
+		t.Run(policy.String(), func(t *testing.T) {
+			p := &workerPool{workers: 1, size: 1, policy: policy}
+			p.shutdown()
+
+			ran := false
+			p.enqueue(job{run: func() { ran = true }}, func(job) { t.Error("job dropped after shutdown") })
+			if !ran {
+				t.Error("job enqueued after shutdown never ran")
+			}
+		})
+	}
+}
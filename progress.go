@@ -0,0 +1,159 @@
+package discordrus
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// PROGRESS_DONE_FIELD_KEY marks the final entry of a long-running operation
+// (any true value). The message is edited one last time and tracking stops.
+const PROGRESS_DONE_FIELD_KEY = "progress_done"
+
+// WithProgressField keeps each long-running operation in one evolving Discord message.
+// The first entry carrying field (e.g. "job_id") is posted as a new message; subsequent
+// entries with the same value edit that message instead of posting new ones, until an entry
+// with PROGRESS_DONE_FIELD_KEY set to true is logged:
+//
+//	log := logger.WithField("job_id", jobID)
+//	log.Info("import started")
+//	log.Infof("imported %d/%d rows", n, total)
+//	log.WithField(discordrus.PROGRESS_DONE_FIELD_KEY, true).Info("import finished")
+//
+// Updates delivered out of order are dropped when a newer one was already sent. Finished
+// operations are remembered for an hour, entries with their value are not sent again.
+func WithProgressField(field string) Option {
+	return func(h *Hook) {
+		h.progressField = field
+	}
+}
+
+const (
+	// progressJobTTL is how long an operation without updates, or a finished one, is remembered
+	progressJobTTL = time.Hour
+
+	// maxProgressJobs bounds the remembered operations, the least recently updated are forgotten first
+	maxProgressJobs = 10000
+)
+
+// progressTracker remembers the Discord message of each running operation
+type progressTracker struct {
+	mu   sync.Mutex
+	jobs map[string]*progressJob
+}
+
+// progressJob orders the updates of one operation
+type progressJob struct {
+	mu        sync.Mutex
+	messageID string
+	threadID  string
+	// seq is the sequence number of the latest entry, taken when it was fired
+	seq uint64
+	// applied is the sequence number of the latest update sent, older ones are dropped
+	applied uint64
+	// done marks a finished operation, kept so late updates cannot reopen it
+	done    bool
+	touched time.Time
+}
+
+// next returns the sequence number of a new update of the operation
+func (t *progressTracker) next(key string) uint64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	j := t.lookup(key)
+	j.seq++
+	return j.seq
+}
+
+// job returns the state of the operation
+func (t *progressTracker) job(key string) *progressJob {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lookup(key)
+}
+
+// lookup returns the job of key, creating it when unknown; t.mu must be held
+func (t *progressTracker) lookup(key string) *progressJob {
+	now := time.Now()
+	if j, ok := t.jobs[key]; ok {
+		j.touched = now
+		return j
+	}
+	if t.jobs == nil {
+		t.jobs = make(map[string]*progressJob)
+	}
+	t.evict(now)
+	j := &progressJob{touched: now}
+	t.jobs[key] = j
+	return j
+}
+
+// evict forgets expired operations and, at the limit, the least recently updated one
+func (t *progressTracker) evict(now time.Time) {
+	var oldestKey string
+	var oldest *progressJob
+	for key, j := range t.jobs {
+		if now.Sub(j.touched) > progressJobTTL {
+			delete(t.jobs, key)
+			continue
+		}
+		if oldest == nil || j.touched.Before(oldest.touched) {
+			oldestKey, oldest = key, j
+		}
+	}
+	if oldest != nil && len(t.jobs) >= maxProgressJobs {
+		delete(t.jobs, oldestKey)
+	}
+}
+
+// progressKey returns the operation key of the entry, or "" when it is not tracked
+func (h *Hook) progressKey(entry *logrus.Entry) (string, bool) {
+	if h.progressField == "" {
+		return "", false
+	}
+	v, ok := entry.Data[h.progressField]
+	if !ok || v == nil {
+		return "", false
+	}
+	done, _ := entry.Data[PROGRESS_DONE_FIELD_KEY].(bool)
+	return fmt.Sprintf("%s\x00%v", h.progressField, v), done
+}
+
+// sendProgress posts the first message of an operation or edits the existing one
+// Updates older than the last one sent, and updates of finished operations, are dropped.
+func (h *Hook) sendProgress(m *message) error {
+	j := h.progress.job(m.URL + "\x00" + m.progressKey)
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	// Update dikirim dari goroutine berbeda, jadi urutannya bisa tertukar
+	if j.done || m.progressSeq <= j.applied {
+		return nil
+	}
+	j.applied = m.progressSeq
+	j.done = m.progressDone
+
+	if j.messageID != "" {
+		m.editID = j.messageID
+		m.threadID = j.threadID
+		_, err := h.send(m)
+		return err
+	}
+
+	m.wait = true
+	resp, err := h.send(m)
+	if err != nil {
+		return err
+	}
+
+	created, err := createdMessage(resp)
+	if err != nil {
+		return err
+	}
+	j.messageID = created.ID
+	j.threadID = m.createdThreadID(created)
+	return nil
+}
@@ -0,0 +1,43 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"net/url"
+)
+
+// queryField renders the query parameters of a request URL as pretty-printed JSON in a field
+// titled name, parameters with a single value as a string and repeated ones as a list
+func queryField(u *url.URL, name string) (EmbedField, bool) {
+	if u == nil || u.RawQuery == "" {
+		return EmbedField{}, false
+	}
+	values, err := url.ParseQuery(u.RawQuery)
+	if err != nil || len(values) == 0 {
+		return EmbedField{Name: name, Value: "```" + previewText(u.RawQuery, maxFieldValueLength) + " ```"}, true
+	}
+
+	params := make(map[string]any, len(values))
+	for k, v := range values {
+		if len(v) == 1 {
+			params[k] = v[0]
+		} else {
+			params[k] = v
+		}
+	}
+	data, err := json.MarshalIndent(params, "", "  ")
+	if err != nil {
+		return EmbedField{}, false
+	}
+	return EmbedField{Name: name, Value: "```" + previewText(string(data), maxFieldValueLength) + "```"}, true
+}
+
+// withoutQuery returns the URL without its query, shown next to a Query field
+func withoutQuery(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.RawQuery == "" {
+		return raw
+	}
+	u.RawQuery = ""
+	u.ForceQuery = false
+	return u.String()
+}
@@ -0,0 +1,43 @@
+package discordrus
+
+import (
+	"bytes"
+	"runtime/debug"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RecoverAndLog recovers a panic of the calling goroutine and logs it at panic level,
+// with the stack of the goroutine attached as panic-stack.txt. Defer it at the top of
+// goroutines that no HTTP recovery middleware covers, e.g. workers and consumers:
+//
+//	go func() {
+//		defer discordrus.RecoverAndLog(logger)
+//		processJobs(ctx)
+//	}()
+//
+// The panic is not raised again, the goroutine ends normally after logging it.
+func RecoverAndLog(logger logrus.FieldLogger) {
+	v := recover()
+	if v == nil {
+		return
+	}
+	stack := debug.Stack()
+
+	entry := logger.WithFields(logrus.Fields{
+		logrus.ErrorKey: PanicError(v),
+		ATTACHMENT_FIELD_KEY: Attachment{
+			Name:   "panic-stack.txt",
+			Reader: bytes.NewReader(stack),
+		},
+	})
+	// logrus selalu panic setelah mencatat entry level panic, panic itu ditelan di sini
+	defer func() {
+		if r := recover(); r != nil {
+			if _, ok := r.(*logrus.Entry); !ok {
+				panic(r)
+			}
+		}
+	}()
+	entry.Logf(logrus.PanicLevel, "recovered panic: %v", v)
+}
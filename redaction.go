@@ -0,0 +1,258 @@
+package discordrus
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// redactedValue replaces redacted values and secrets in the configuration view
+const redactedValue = "[REDACTED]"
+
+// RedactionRules describe which parts of the captured request and message are
+// replaced by "[REDACTED]" before the payload is built
+type RedactionRules struct {
+	// Headers are request header names, matched case-insensitively
+	Headers []string
+	// BodyKeys are JSON object keys, form and query parameter names and entry field keys,
+	// matched case-insensitively
+	BodyKeys []string
+	// Patterns are applied to request bodies, manual header strings, the log message and
+	// entry fields; every match is replaced
+	Patterns []*regexp.Regexp
+}
+
+// DefaultRedactionRules returns the rules used by hooks created with NewHook
+func DefaultRedactionRules() RedactionRules {
+	return RedactionRules{
+		Headers:  []string{"Authorization", "Cookie", "Set-Cookie", "X-Api-Key"},
+		BodyKeys: []string{"password", "token"},
+	}
+}
+
+// WithRedaction replaces the redaction rules, pass RedactionRules{} to disable redaction
+func WithRedaction(rules RedactionRules) Option {
+	return func(h *Hook) {
+		h.redaction = rules
+	}
+}
+
+// WithRedactedHeaders adds request header names to redact
+func WithRedactedHeaders(names ...string) Option {
+	return func(h *Hook) {
+		h.redaction.Headers = append(h.redaction.Headers, names...)
+	}
+}
+
+// WithRedactedBodyKeys adds JSON keys and form/query parameter names to redact
+func WithRedactedBodyKeys(keys ...string) Option {
+	return func(h *Hook) {
+		h.redaction.BodyKeys = append(h.redaction.BodyKeys, keys...)
+	}
+}
+
+// WithRedactionPatterns adds patterns whose matches are redacted, e.g.
+//
+//	discordrus.WithRedactionPatterns(regexp.MustCompile(`\b\d{16}\b`))
+func WithRedactionPatterns(patterns ...*regexp.Regexp) Option {
+	return func(h *Hook) {
+		h.redaction.Patterns = append(h.redaction.Patterns, patterns...)
+	}
+}
+
+// empty reports whether no rule is configured
+func (r *RedactionRules) empty() bool {
+	return len(r.Headers) == 0 && len(r.BodyKeys) == 0 && len(r.Patterns) == 0
+}
+
+// redactPayload redacts a captured request payload in place
+func (r *RedactionRules) redactPayload(p *LoggerHttpRequestPayload) {
+	if r.empty() {
+		return
+	}
+	if p.Request != nil {
+		for _, name := range r.Headers {
+			if p.Request.Header.Get(name) != "" {
+				p.Request.Header.Set(name, redactedValue)
+			}
+		}
+		if p.Request.URL != nil {
+			p.Request.URL.RawQuery = r.redactQuery(p.Request.URL.RawQuery)
+		}
+
+		if p.Request.Body == nil || p.Request.Body == http.NoBody {
+			return
+		}
+		contentType := p.Request.Header.Get("Content-Type")
+		if strings.Contains(contentType, "multipart/form-data") {
+			// Multipart tidak diubah agar boundary dan file tetap utuh, nilai form
+			// di-redact saat di-render, lihat redactFormValues
+			return
+		}
+		body, _ := io.ReadAll(p.Request.Body)
+		if strings.Contains(contentType, "application/x-www-form-urlencoded") {
+			body = []byte(r.redactQuery(string(body)))
+		} else {
+			body = r.redactJSON(body)
+		}
+		body = []byte(r.redactText(string(body)))
+		p.Request.Body = io.NopCloser(bytes.NewReader(body))
+		p.Request.ContentLength = int64(len(body))
+		return
+	}
+
+	if u, err := url.Parse(p.URL); err == nil && u.RawQuery != "" {
+		u.RawQuery = r.redactQuery(u.RawQuery)
+		p.URL = u.String()
+	}
+	p.BodyString = r.redactText(string(r.redactJSON([]byte(p.BodyString))))
+	p.Headers = r.redactText(r.redactHeaderLines(p.Headers))
+}
+
+// redactText replaces every pattern match in s
+func (r *RedactionRules) redactText(s string) string {
+	for _, p := range r.Patterns {
+		s = p.ReplaceAllString(s, redactedValue)
+	}
+	return s
+}
+
+// redactField redacts the rendered value of the entry field key: values of body keys are
+// replaced, JSON values (structs and maps) have their body keys and pattern matches redacted
+func (r *RedactionRules) redactField(key, value string) string {
+	if r.isBodyKey(key) {
+		return redactedValue
+	}
+	return r.redactText(string(r.redactJSON([]byte(value))))
+}
+
+// isBodyKey reports whether key matches one of the body keys
+func (r *RedactionRules) isBodyKey(key string) bool {
+	for _, k := range r.BodyKeys {
+		if strings.EqualFold(k, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactJSON redacts the body keys found anywhere in a JSON document
+// Bodies that are not valid JSON, or contain none of the keys, are returned unchanged.
+func (r *RedactionRules) redactJSON(body []byte) []byte {
+	if len(r.BodyKeys) == 0 || !json.Valid(body) {
+		return body
+	}
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return body
+	}
+	if !r.redactJSONValue(v) {
+		return body
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return body
+	}
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
+
+// redactJSONValue walks a decoded JSON value and reports whether anything was redacted
+func (r *RedactionRules) redactJSONValue(v any) bool {
+	changed := false
+	switch val := v.(type) {
+	case map[string]any:
+		for k, child := range val {
+			if r.isBodyKey(k) {
+				val[k] = redactedValue
+				changed = true
+			} else if r.redactJSONValue(child) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, child := range val {
+			if r.redactJSONValue(child) {
+				changed = true
+			}
+		}
+	}
+	return changed
+}
+
+// redactQuery redacts the body keys and pattern matches in a URL-encoded query or form
+func (r *RedactionRules) redactQuery(raw string) string {
+	if raw == "" || (len(r.BodyKeys) == 0 && len(r.Patterns) == 0) {
+		return raw
+	}
+	values, err := url.ParseQuery(raw)
+	if err != nil {
+		return raw
+	}
+	changed := false
+	for k, vs := range values {
+		if r.isBodyKey(k) {
+			values[k] = []string{redactedValue}
+			changed = true
+			continue
+		}
+		for i, v := range vs {
+			if redacted := r.redactText(v); redacted != v {
+				vs[i] = redacted
+				changed = true
+			}
+		}
+	}
+	if !changed {
+		return raw
+	}
+	// Biarkan "[REDACTED]" tetap terbaca di URL
+	return strings.ReplaceAll(values.Encode(), url.QueryEscape(redactedValue), redactedValue)
+}
+
+// redactFormValues returns a redacted copy of parsed form values: values of body keys
+// are replaced and pattern matches redacted. The values themselves are not modified.
+func (r *RedactionRules) redactFormValues(values map[string][]string) map[string][]string {
+	redacted := make(map[string][]string, len(values))
+	for k, vs := range values {
+		if r.isBodyKey(k) {
+			redacted[k] = []string{redactedValue}
+			continue
+		}
+		copied := make([]string, len(vs))
+		for i, v := range vs {
+			copied[i] = r.redactText(v)
+		}
+		redacted[k] = copied
+	}
+	return redacted
+}
+
+// redactHeaderLines redacts "Name: value" lines of a manual header string
+func (r *RedactionRules) redactHeaderLines(headers string) string {
+	if headers == "" || len(r.Headers) == 0 {
+		return headers
+	}
+	lines := strings.Split(headers, "\n")
+	for i, line := range lines {
+		name, _, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		for _, h := range r.Headers {
+			if strings.EqualFold(strings.TrimSpace(name), h) {
+				lines[i] = name + ": " + redactedValue
+				break
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,159 @@
+package discordrus
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// RESPONSE_FIELD_KEY is the key used to pass HTTP response data in logrus fields
+	RESPONSE_FIELD_KEY = "response"
+
+	// maxResponseCaptureSize caps how much of a logged response body is copied
+	maxResponseCaptureSize = 64 << 10
+)
+
+// LoggerHttpResponsePayload holds HTTP response information for logging, e.g. the upstream
+// response of a failed API call logged together with its request:
+//
+//	log.WithFields(logrus.Fields{
+//		discordrus.REQUEST_FIELD_KEY:  discordrus.LoggerHttpRequestPayload{Request: req},
+//		discordrus.RESPONSE_FIELD_KEY: discordrus.LoggerHttpResponsePayload{Response: resp, Latency: time.Since(start)},
+//	}).Error("payment provider failed")
+//
+// You can either provide a *http.Response or fill the fields manually. The response body
+// is read up to 64 KB and restored, so the caller can still read it afterwards.
+type LoggerHttpResponsePayload struct {
+	// Response is the actual HTTP response (preferred)
+	Response *http.Response
+
+	// Latency is the time between sending the request and receiving the response
+	Latency time.Duration
+
+	// Manual fields - used when Response is nil
+	StatusCode int    // HTTP status code
+	BodyString string // Response body as string
+	Headers    string // Response headers as string
+}
+
+// capturedResponse is the copy of a response payload taken when the entry is fired
+type capturedResponse struct {
+	StatusCode   int
+	Header       http.Header // nil for manually filled payloads
+	Headers      string
+	Body         string
+	BodyCaptured bool
+	Latency      time.Duration
+}
+
+// captureResponsePayload makes a copy of the response payload in entry.Data[RESPONSE_FIELD_KEY]
+// so it stays valid after the caller has closed the response. It returns nil if there is none.
+// The body is only copied when withBody is true.
+func captureResponsePayload(entry *logrus.Entry, withBody bool) *capturedResponse {
+	var p LoggerHttpResponsePayload
+	switch v := entry.Data[RESPONSE_FIELD_KEY].(type) {
+	case LoggerHttpResponsePayload:
+		p = v
+	case *LoggerHttpResponsePayload:
+		if v == nil {
+			return nil
+		}
+		p = *v
+	case capturedResponse:
+		return &v
+	default:
+		return nil
+	}
+
+	c := &capturedResponse{Latency: p.Latency, BodyCaptured: withBody}
+	if p.Response == nil {
+		c.StatusCode = p.StatusCode
+		c.Headers = p.Headers
+		if withBody {
+			c.Body = p.BodyString
+		}
+		return c
+	}
+
+	c.StatusCode = p.Response.StatusCode
+	c.Header = p.Response.Header.Clone()
+	if withBody && p.Response.Body != nil && p.Response.Body != http.NoBody {
+		body, _ := io.ReadAll(io.LimitReader(p.Response.Body, maxResponseCaptureSize))
+		c.Body = string(body)
+
+		// Kembalikan body agar caller tetap bisa membaca response secara utuh
+		p.Response.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(bytes.NewReader(body), p.Response.Body), p.Response.Body}
+	}
+	return c
+}
+
+// redactResponse redacts a captured response in place
+func (r *RedactionRules) redactResponse(c *capturedResponse) {
+	if r.empty() {
+		return
+	}
+	for _, name := range r.Headers {
+		if c.Header.Get(name) != "" {
+			c.Header.Set(name, redactedValue)
+		}
+	}
+	c.Headers = r.redactText(r.redactHeaderLines(c.Headers))
+	c.Body = r.redactText(string(r.redactJSON([]byte(c.Body))))
+}
+
+// responseEmbed renders the captured response of the entry, if any, along with the
+// attachment of a body too long for the embed
+func (h *Hook) responseEmbed(entry *logrus.Entry, color int) (Embed, *Attachment, bool) {
+	c := captureResponsePayload(entry, true)
+	if c == nil {
+		return Embed{}, nil, false
+	}
+
+	var file *Attachment
+	labels := h.labelSet()
+
+	var fields []EmbedField
+	if c.StatusCode != 0 {
+		fields = append(fields, EmbedField{
+			Name:   labels.Status,
+			Value:  fmt.Sprintf("```%d %s ```", c.StatusCode, http.StatusText(c.StatusCode)),
+			Inline: true,
+		})
+	}
+	if c.Latency > 0 {
+		fields = append(fields, EmbedField{
+			Name:   labels.Latency,
+			Value:  "```" + c.Latency.Round(time.Millisecond).String() + " ```",
+			Inline: true,
+		})
+	}
+	if c.BodyCaptured && c.Body != "" {
+		if summary, ok := h.summarizeBody([]byte(c.Body)); ok {
+			fields = append(fields, summary)
+		} else {
+			var field EmbedField
+			field, file = h.bodyField([]byte(c.Body), c.Header.Get("Content-Type"), responseBodyFile)
+			fields = append(fields, field)
+		}
+	}
+	if c.Header != nil && h.headerRendering != nil {
+		if field, ok := h.headerRendering.field(labels.Headers, c.Header, h.headerValueLength); ok {
+			fields = append(fields, field)
+		}
+	} else if c.Headers != "" {
+		fields = append(fields, EmbedField{
+			Name:  labels.Headers,
+			Value: "```" + c.Headers + " ```",
+		})
+	}
+
+	return Embed{Title: labels.Response, Fields: fields, Color: color}, file, true
+}
@@ -0,0 +1,17 @@
+package discordrus
+
+import "github.com/murbagus/discordrus/sender"
+
+// RetryPolicy controls how deliveries are retried after transient failures, see sender.RetryPolicy
+type RetryPolicy = sender.RetryPolicy
+
+// DefaultRetryPolicy is the retry policy of hooks created with NewHook
+var DefaultRetryPolicy = sender.DefaultRetryPolicy
+
+// WithRetryPolicy sets the retry policy for transient failures
+// Use RetryPolicy{} to disable retrying.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(h *Hook) {
+		h.retry = p
+	}
+}
@@ -0,0 +1,80 @@
+package discordrus
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithCategoryField sets the entry field holding the log category (e.g. "subsystem")
+// used to select a webhook from the routes configured with WithCategoryRoutes
+func WithCategoryField(field string) Option {
+	return func(h *Hook) {
+		h.categoryField = field
+	}
+}
+
+// WithCategoryRoutes maps categories to webhook URLs, e.g.
+//
+//	discordrus.WithCategoryRoutes(map[string]string{
+//		"db":       dbTeamWebhook,
+//		"payments": paymentsWebhook,
+//	})
+//
+// Entries with an unknown or missing category are sent to the hook's default webhook.
+func WithCategoryRoutes(routes map[string]string) Option {
+	return func(h *Hook) {
+		if h.categoryRoutes == nil {
+			h.categoryRoutes = make(map[string]string, len(routes))
+		}
+		for category, webhookURL := range routes {
+			h.categoryRoutes[category] = webhookURL
+		}
+	}
+}
+
+// WithLevelRoutes maps log levels to webhook URLs, e.g. errors to #alerts and warnings to #warnings:
+//
+//	discordrus.WithLevelRoutes(map[logrus.Level]string{
+//		logrus.ErrorLevel: alertsWebhook,
+//		logrus.WarnLevel:  warningsWebhook,
+//	})
+//
+// Category routes take precedence; levels without a route are sent to the hook's default webhook.
+func WithLevelRoutes(routes map[logrus.Level]string) Option {
+	return func(h *Hook) {
+		if h.levelRoutes == nil {
+			h.levelRoutes = make(map[logrus.Level]string, len(routes))
+		}
+		for level, webhookURL := range routes {
+			h.levelRoutes[level] = webhookURL
+		}
+	}
+}
+
+// NewRouterHook creates a hook that sends each level to its own webhook
+// The hook processes exactly the levels of routes.
+func NewRouterHook(routes map[logrus.Level]string, opts ...Option) *Hook {
+	levels := make([]logrus.Level, 0, len(routes))
+	for _, l := range AllLevels {
+		if _, ok := routes[l]; ok {
+			levels = append(levels, l)
+		}
+	}
+	return NewHook("", append([]Option{WithLevels(levels...), WithLevelRoutes(routes)}, opts...)...)
+}
+
+// webhookURLFor returns the destination webhook of the entry
+func (h *Hook) webhookURLFor(entry *logrus.Entry) string {
+	if h.categoryField != "" && len(h.categoryRoutes) > 0 {
+		if v, ok := entry.Data[h.categoryField]; ok && v != nil {
+			if webhookURL, ok := h.categoryRoutes[fmt.Sprint(v)]; ok && webhookURL != "" {
+				return webhookURL
+			}
+		}
+	}
+	if webhookURL, ok := h.levelRoutes[entry.Level]; ok && webhookURL != "" {
+		return webhookURL
+	}
+	return h.HookUrl
+}
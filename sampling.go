@@ -0,0 +1,49 @@
+package discordrus
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithSampling delivers only 1 in rate entries of the given levels, e.g. for chatty
+// services that log many warnings. Delivered entries are titled e.g. "WARNING (sampled 1/100)".
+// The first entry of a level is always delivered, a rate of 1 or less disables sampling:
+//
+//	discordrus.WithSampling(100, logrus.WarnLevel, logrus.InfoLevel)
+func WithSampling(rate int, levels ...logrus.Level) Option {
+	return func(h *Hook) {
+		if h.sampling == nil {
+			h.sampling = make(map[logrus.Level]*sampler, len(levels))
+		}
+		for _, level := range levels {
+			if rate <= 1 {
+				delete(h.sampling, level)
+				continue
+			}
+			h.sampling[level] = &sampler{rate: rate}
+		}
+	}
+}
+
+// sampler counts the entries of a sampled level
+type sampler struct {
+	rate int
+
+	mu   sync.Mutex
+	seen int
+}
+
+// sample reports whether the entry of the level is delivered and its sampling rate
+func (h *Hook) sample(level logrus.Level) (int, bool) {
+	s, ok := h.sampling[level]
+	if !ok {
+		return 1, true
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	keep := s.seen%s.rate == 0
+	s.seen++
+	return s.rate, keep
+}
@@ -0,0 +1,63 @@
+package sender
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+// ErrPayloadTooLarge is returned when Discord rejects the request body as too large (HTTP 413)
+// It can be matched with errors.Is on an *ErrDiscordAPI
+var ErrPayloadTooLarge = eris.New("Discord webhook payload is too large")
+
+// ErrRateLimited is returned when a message is still rate limited after all retries
+type ErrRateLimited struct {
+	RetryAfter time.Duration // Delay requested by Discord in the last response
+}
+
+func (e *ErrRateLimited) Error() string {
+	return fmt.Sprintf("Discord webhook is rate limited, retry after %s", e.RetryAfter)
+}
+
+// ErrDiscordAPI is returned when Discord answers with a non-success status code
+type ErrDiscordAPI struct {
+	StatusCode int    // HTTP status code of the response
+	Code       int    // Discord JSON error code, 0 if not present
+	Message    string // Discord error message, if present
+}
+
+func (e *ErrDiscordAPI) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("Failed to post to Discord webhook: %d %s (code %d)", e.StatusCode, e.Message, e.Code)
+	}
+	return fmt.Sprintf("Failed to post to Discord webhook: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Unwrap makes errors.Is(err, ErrPayloadTooLarge) report true for 413 responses
+func (e *ErrDiscordAPI) Unwrap() error {
+	if e.StatusCode == http.StatusRequestEntityTooLarge {
+		return ErrPayloadTooLarge
+	}
+	return nil
+}
+
+// APIError builds an *ErrDiscordAPI from a failed response, e.g. of a request sent with Do
+// Discord error bodies look like {"code": 50035, "message": "Invalid Form Body"}
+func APIError(resp *http.Response) *ErrDiscordAPI {
+	e := &ErrDiscordAPI{StatusCode: resp.StatusCode}
+
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	var body struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal(data, &body) == nil {
+		e.Code = body.Code
+		e.Message = body.Message
+	}
+	return e
+}
@@ -0,0 +1,227 @@
+package sender
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/rotisserie/eris"
+)
+
+// MaxFiles is the number of files Discord accepts in a single webhook message
+const MaxFiles = 10
+
+// Message is a webhook message: the JSON payload and the files uploaded with it
+type Message struct {
+	WebhookURL string // Destination webhook
+	Payload    []byte // JSON payload, see https://discord.com/developers/docs/resources/webhook#execute-webhook
+	Files      []File // At most MaxFiles
+
+	ThreadID string // Posts into an existing thread (?thread_id=)
+	EditID   string // Edits the previously sent message with this id instead of posting a new one
+	Wait     bool   // Asks Discord to return the created message (?wait=true)
+
+	// Components asks Discord to render the components of the payload (?with_components=true)
+	Components bool
+
+	// boundary is kept for the lifetime of the message so every rebuilt
+	// multipart body matches the Content-Type header
+	boundary string
+}
+
+// File is a file uploaded together with the payload
+// Its content comes either from Data or is streamed from Open, which is called every time
+// the body is built; the returned reader is closed after use if it implements io.Closer.
+type File struct {
+	Name string
+	Data []byte
+	Open func() (io.Reader, error)
+}
+
+// streaming reports whether the message has files that are streamed from Open
+func (m *Message) streaming() bool {
+	for _, f := range m.Files {
+		if f.Open != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// method returns the HTTP method of the request
+func (m *Message) method() string {
+	if m.EditID != "" {
+		return http.MethodPatch
+	}
+	return http.MethodPost
+}
+
+// RequestURL returns the URL the message is sent to
+// Edits go to {webhook}/messages/{id}, see https://discord.com/developers/docs/resources/webhook
+func (m *Message) RequestURL() (string, error) {
+	if m.EditID == "" && !m.Wait && m.ThreadID == "" && !m.Components {
+		return m.WebhookURL, nil
+	}
+
+	u, err := url.Parse(m.WebhookURL)
+	if err != nil {
+		return "", eris.Wrap(err, "invalid webhook url")
+	}
+	if m.EditID != "" {
+		u.Path = strings.TrimSuffix(u.Path, "/") + "/messages/" + url.PathEscape(m.EditID)
+	}
+	if m.Wait || m.ThreadID != "" || m.Components {
+		q := u.Query()
+		if m.Wait {
+			q.Set("wait", "true")
+		}
+		if m.ThreadID != "" {
+			q.Set("thread_id", m.ThreadID)
+		}
+		if m.Components {
+			q.Set("with_components", "true")
+		}
+		u.RawQuery = q.Encode()
+	}
+	return u.String(), nil
+}
+
+// contentType returns the Content-Type of the request body
+func (m *Message) contentType() string {
+	if len(m.Files) == 0 {
+		return "application/json"
+	}
+	return "multipart/form-data; boundary=" + m.multipartBoundary()
+}
+
+func (m *Message) multipartBoundary() string {
+	if m.boundary == "" {
+		var b [16]byte
+		_, _ = rand.Read(b[:])
+		m.boundary = hex.EncodeToString(b[:])
+	}
+	return m.boundary
+}
+
+// encode returns the complete request body for the message
+// Messages with files are sent as multipart/form-data, others as plain JSON
+func (m *Message) encode() ([]byte, error) {
+	if len(m.Files) == 0 {
+		return m.Payload, nil
+	}
+
+	var body bytes.Buffer
+	if err := m.writeMultipart(&body); err != nil {
+		return nil, err
+	}
+	return body.Bytes(), nil
+}
+
+// writeMultipart writes payload_json and all files as a multipart body into w
+func (m *Message) writeMultipart(w io.Writer) error {
+	mp := multipart.NewWriter(w)
+	if err := mp.SetBoundary(m.multipartBoundary()); err != nil {
+		return err
+	}
+
+	// Tambahkan payload_json field
+	part, err := mp.CreateFormField("payload_json")
+	if err != nil {
+		return eris.Wrap(err, "failed to create multipart field")
+	}
+	if _, err := part.Write(m.payloadJSON()); err != nil {
+		return err
+	}
+
+	// Tambahkan file attachment
+	for i, f := range m.Files {
+		filePart, err := mp.CreateFormFile(fmt.Sprintf("files[%d]", i), f.Name)
+		if err != nil {
+			return eris.Wrap(err, "failed to create multipart file")
+		}
+		if f.Open == nil {
+			if _, err := filePart.Write(f.Data); err != nil {
+				return err
+			}
+			continue
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			return eris.Wrapf(err, "failed to open attachment %s", f.Name)
+		}
+		_, err = io.Copy(filePart, r)
+		if c, ok := r.(io.Closer); ok {
+			c.Close()
+		}
+		if err != nil {
+			return eris.Wrapf(err, "failed to stream attachment %s", f.Name)
+		}
+	}
+
+	return mp.Close()
+}
+
+// attachmentMetadata describes an uploaded file in payload_json
+type attachmentMetadata struct {
+	ID       int    `json:"id"`
+	Filename string `json:"filename"`
+}
+
+// payloadJSON returns the payload with the attachments metadata matching the files[n]
+// parts. Payloads that already list their attachments, or are not a JSON object, are
+// used as is.
+func (m *Message) payloadJSON() []byte {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(m.Payload, &fields); err != nil || fields == nil {
+		return m.Payload
+	}
+	if _, ok := fields["attachments"]; ok {
+		return m.Payload
+	}
+
+	attachments := make([]attachmentMetadata, len(m.Files))
+	for i, f := range m.Files {
+		attachments[i] = attachmentMetadata{ID: i, Filename: f.Name}
+	}
+	raw, err := json.Marshal(attachments)
+	if err != nil {
+		return m.Payload
+	}
+	fields["attachments"] = raw
+	payload, err := json.Marshal(fields)
+	if err != nil {
+		return m.Payload
+	}
+	return payload
+}
+
+// bodyFactory returns a function producing a fresh request body for every call,
+// so a request can be re-sent after its previous body has been consumed
+// raw is the complete body when it had to be built in memory (e.g. for signing)
+func (m *Message) bodyFactory(buffered bool) (getBody func() (io.ReadCloser, error), raw []byte, err error) {
+	if !m.streaming() || buffered {
+		raw, err := m.encode()
+		if err != nil {
+			return nil, nil, err
+		}
+		return func() (io.ReadCloser, error) {
+			return io.NopCloser(bytes.NewReader(raw)), nil
+		}, raw, nil
+	}
+
+	return func() (io.ReadCloser, error) {
+		pr, pw := io.Pipe()
+		go func() {
+			pw.CloseWithError(m.writeMultipart(pw))
+		}()
+		return pr, nil
+	}, nil, nil
+}
@@ -0,0 +1,146 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// maxRateLimitRetries is how many times a rate limited message is re-sent before giving up
+	maxRateLimitRetries = 5
+
+	// maxRateLimitDelay caps the delay taken from Discord's rate limit headers
+	maxRateLimitDelay = time.Minute
+)
+
+// RateLimits holds the rate limit state of every webhook, so senders sharing it
+// (see WithRateLimits) pause together when Discord rate limits a webhook
+// The zero value is ready to use
+type RateLimits struct {
+	mu       sync.Mutex
+	limiters map[string]*rateLimiter
+}
+
+// limiter returns the rate limiter of the webhook
+// Discord applies rate limits per webhook, so every webhook has its own limiter
+func (l *RateLimits) limiter(webhookURL string) *rateLimiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.limiters == nil {
+		l.limiters = make(map[string]*rateLimiter)
+	}
+	r, ok := l.limiters[webhookURL]
+	if !ok {
+		r = &rateLimiter{}
+		l.limiters[webhookURL] = r
+	}
+	return r
+}
+
+// Blocked reports whether sends to the webhook are currently paused
+func (l *RateLimits) Blocked(webhookURL string) bool {
+	return l.limiter(webhookURL).blocked()
+}
+
+// rateLimiter keeps messages queued while the webhook is rate limited
+// The zero value is ready to use
+type rateLimiter struct {
+	mu    sync.Mutex
+	until time.Time
+}
+
+// wait blocks until the webhook may be called again or ctx is done
+func (r *rateLimiter) wait(ctx context.Context) error {
+	for {
+		r.mu.Lock()
+		d := time.Until(r.until)
+		r.mu.Unlock()
+		if d <= 0 {
+			return nil
+		}
+		if err := sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+}
+
+// blocked reports whether sends are currently paused
+func (r *rateLimiter) blocked() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return time.Now().Before(r.until)
+}
+
+// update reads Discord's rate limit headers from the response and blocks
+// further sends if needed. It returns the delay that was applied.
+//
+// Discord sends Retry-After (and retry_after in the JSON body) on 429 responses,
+// and X-RateLimit-Remaining / X-RateLimit-Reset-After on every response.
+func (r *rateLimiter) update(resp *http.Response) time.Duration {
+	var delay time.Duration
+	if resp.StatusCode == http.StatusTooManyRequests {
+		delay = retryAfter(resp)
+		if delay <= 0 {
+			delay = time.Second
+		}
+	} else if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		delay = parseSeconds(resp.Header.Get("X-RateLimit-Reset-After"))
+	}
+	if delay <= 0 {
+		return 0
+	}
+	if delay > maxRateLimitDelay {
+		delay = maxRateLimitDelay
+	}
+
+	r.mu.Lock()
+	if until := time.Now().Add(delay); until.After(r.until) {
+		r.until = until
+	}
+	r.mu.Unlock()
+	return delay
+}
+
+// retryAfter extracts the delay of a 429 response
+func retryAfter(resp *http.Response) time.Duration {
+	if d := parseSeconds(resp.Header.Get("Retry-After")); d > 0 {
+		return d
+	}
+	if d := parseSeconds(resp.Header.Get("X-RateLimit-Reset-After")); d > 0 {
+		return d
+	}
+
+	// Fallback ke body JSON: {"message": "...", "retry_after": 0.35, "global": false}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+	// Closer asli tetap dipakai agar koneksi dilepas saat body ditutup
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(data), resp.Body), resp.Body}
+	var body struct {
+		RetryAfter float64 `json:"retry_after"`
+	}
+	if json.Unmarshal(data, &body) == nil && body.RetryAfter > 0 {
+		return time.Duration(body.RetryAfter * float64(time.Second))
+	}
+	return 0
+}
+
+// parseSeconds parses a (possibly fractional) number of seconds
+func parseSeconds(v string) time.Duration {
+	if v == "" {
+		return 0
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f <= 0 {
+		return 0
+	}
+	return time.Duration(f * float64(time.Second))
+}
@@ -0,0 +1,109 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSendHonoursRetryAfter(t *testing.T) {
+	var mu sync.Mutex
+	var arrivals []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		arrivals = append(arrivals, time.Now())
+		first := len(arrivals) == 1
+		mu.Unlock()
+		if first {
+			w.Header().Set("Retry-After", "0.2")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	s := New()
+	res, err := s.Send(context.Background(), &Message{WebhookURL: srv.URL, Payload: []byte(`{"content":"hi"}`)})
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if res.RateLimited != 1 || res.Attempts != 2 {
+		t.Errorf("Result = %+v, want 1 rate limited response and 2 attempts", res)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	if waited := arrivals[1].Sub(arrivals[0]); waited < 200*time.Millisecond {
+		t.Errorf("retried after %s, want at least the Retry-After of 200ms", waited)
+	}
+}
+
+func TestSendGivesUpWhenRateLimited(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "0.01")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	res, err := New().Send(context.Background(), &Message{WebhookURL: srv.URL, Payload: []byte(`{}`)})
+	var limited *ErrRateLimited
+	if !errors.As(err, &limited) {
+		t.Fatalf("Send() = %v, want *ErrRateLimited", err)
+	}
+	if res.RateLimited != maxRateLimitRetries+1 {
+		t.Errorf("got %d rate limited responses, want %d", res.RateLimited, maxRateLimitRetries+1)
+	}
+	if !Retryable(err) {
+		t.Error("rate limited error is not retryable")
+	}
+}
+
+func TestRateLimitDelay(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		headers map[string]string
+		body    string
+		want    time.Duration
+	}{
+		{"retry after", http.StatusTooManyRequests, map[string]string{"Retry-After": "1.5"}, "", 1500 * time.Millisecond},
+		{"retry after capped", http.StatusTooManyRequests, map[string]string{"Retry-After": "3600"}, "", maxRateLimitDelay},
+		{"reset after", http.StatusTooManyRequests, map[string]string{"X-RateLimit-Reset-After": "2"}, "", 2 * time.Second},
+		{"json body", http.StatusTooManyRequests, nil, `{"message": "You are being rate limited.", "retry_after": 0.35}`, 350 * time.Millisecond},
+		{"json body capped", http.StatusTooManyRequests, nil, `{"retry_after": 86400}`, maxRateLimitDelay},
+		{"no delay given", http.StatusTooManyRequests, nil, "", time.Second},
+		{"bucket exhausted", http.StatusNoContent, map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset-After": "0.5"}, "", 500 * time.Millisecond},
+		{"bucket left", http.StatusNoContent, map[string]string{"X-RateLimit-Remaining": "3", "X-RateLimit-Reset-After": "0.5"}, "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			for k, v := range tt.headers {
+				rec.Header().Set(k, v)
+			}
+			rec.WriteHeader(tt.status)
+			rec.WriteString(tt.body)
+			resp := rec.Result()
+
+			var r rateLimiter
+			if got := r.update(resp); got != tt.want {
+				t.Errorf("update() = %s, want %s", got, tt.want)
+			}
+			if got := r.blocked(); got != (tt.want > 0) {
+				t.Errorf("blocked() = %v, want %v", got, tt.want > 0)
+			}
+			if tt.body != "" {
+				// Body tetap bisa dibaca setelah retry_after diambil darinya
+				rest, err := io.ReadAll(resp.Body)
+				if err != nil || string(rest) != tt.body {
+					t.Errorf("body after update = %q, want %q", rest, tt.body)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// defaultMaxDelay bounds the retry delays of policies without MaxDelay
+const defaultMaxDelay = time.Minute
+
+// RetryPolicy controls how deliveries are retried after transient failures:
+// network errors and HTTP 500, 502, 503 and 504 responses.
+// Rate limited requests (HTTP 429) are retried separately, after the delay given by Discord.
+type RetryPolicy struct {
+	MaxRetries int           // Number of retries after the first attempt, 0 disables retrying
+	BaseDelay  time.Duration // Delay before the first retry, doubled for every further retry
+	MaxDelay   time.Duration // Upper bound of a single delay, 0 means one minute
+	Jitter     bool          // Randomizes each delay between half and the full value
+}
+
+// DefaultRetryPolicy is the retry policy of senders created with New
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   5 * time.Second,
+	Jitter:     true,
+}
+
+// WithRetryPolicy sets the retry policy for transient failures
+// Use RetryPolicy{} to disable retrying.
+func WithRetryPolicy(p RetryPolicy) Option {
+	return func(s *Sender) {
+		s.retry = p
+	}
+}
+
+// Delay returns the time to wait before the given retry (starting at 0)
+func (p RetryPolicy) Delay(retry int) time.Duration {
+	ceiling := p.MaxDelay
+	if ceiling <= 0 {
+		ceiling = defaultMaxDelay
+	}
+	d := max(p.BaseDelay, 0)
+	// Berhenti menggandakan setelah batas tercapai, sehingga d tidak bisa overflow
+	for i := 0; i < retry && d > 0 && d < ceiling; i++ {
+		d *= 2
+	}
+	d = min(d, ceiling)
+	if p.Jitter && d > 1 {
+		d = d/2 + rand.N(d/2)
+	}
+	return d
+}
+
+// Retryable reports whether a failed Send may succeed when the message is sent again later,
+// i.e. it failed on the network, with a transient server error or a rate limit
+func Retryable(err error) bool {
+	var limited *ErrRateLimited
+	return errors.As(err, &limited) || isTransient(err)
+}
+
+// isTransient reports whether a failed delivery may succeed when retried
+func isTransient(err error) bool {
+	var apiErr *ErrDiscordAPI
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+
+	// Error jaringan (termasuk timeout) dibungkus dalam *url.Error oleh http.Client
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && !errors.Is(err, context.Canceled)
+}
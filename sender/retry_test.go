@@ -0,0 +1,40 @@
+package sender
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRetryPolicyDelay(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy RetryPolicy
+		retry  int
+		want   time.Duration
+	}{
+		{"first retry", RetryPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second}, 0, time.Second},
+		{"doubled", RetryPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second}, 2, 4 * time.Second},
+		{"capped", RetryPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second}, 5, 10 * time.Second},
+		{"default cap", RetryPolicy{BaseDelay: time.Second}, 40, defaultMaxDelay},
+		{"default cap huge retry", RetryPolicy{BaseDelay: time.Second}, 1 << 20, defaultMaxDelay},
+		{"base above cap", RetryPolicy{BaseDelay: time.Hour, MaxDelay: time.Minute}, 0, time.Minute},
+		{"zero base", RetryPolicy{MaxDelay: time.Minute}, 10, 0},
+		{"negative base", RetryPolicy{BaseDelay: -time.Second}, 3, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Delay(tt.retry); got != tt.want {
+				t.Errorf("Delay(%d) = %v, want %v", tt.retry, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicyDelayJitter(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, Jitter: true}
+	for retry := 0; retry < 64; retry++ {
+		if d := p.Delay(retry); d <= 0 || d > defaultMaxDelay {
+			t.Fatalf("Delay(%d) = %v, want within (0, %v]", retry, d, defaultMaxDelay)
+		}
+	}
+}
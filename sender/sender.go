@@ -0,0 +1,292 @@
+// Package sender delivers messages to Discord webhooks: multipart building with streamed
+// files, per-webhook rate limiting, retries of transient failures, optional request signing
+// and an asynchronous queue. It is the delivery layer of the discordrus hook and can be used
+// on its own, e.g. by batch jobs and CLIs:
+//
+//	s := sender.New(sender.WithTimeout(5 * time.Second))
+//	_, err := s.Send(ctx, &sender.Message{
+//		WebhookURL: webhookURL,
+//		Payload:    []byte(`{"content": "nightly export finished"}`),
+//	})
+package sender
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/rotisserie/eris"
+)
+
+const (
+	// DefaultTimeout is the time a single request may take, see WithTimeout
+	DefaultTimeout = 10 * time.Second
+
+	// maxResponseBodySize caps how much of a successful Discord response is read
+	maxResponseBodySize = 64 << 10
+)
+
+// Sender posts messages to Discord webhooks
+// A Sender is safe for concurrent use.
+type Sender struct {
+	client        *http.Client
+	retry         RetryPolicy
+	timeout       time.Duration
+	signingSecret string
+	limits        *RateLimits
+
+	wg sync.WaitGroup
+}
+
+// Option configures a Sender
+type Option func(*Sender)
+
+// WithHTTPClient sets the client used for all requests (default: a new http.Client)
+func WithHTTPClient(c *http.Client) Option {
+	return func(s *Sender) {
+		s.client = c
+	}
+}
+
+// WithTimeout sets the time a single request may take (default DefaultTimeout), 0 disables
+// the timeout
+func WithTimeout(d time.Duration) Option {
+	return func(s *Sender) {
+		s.timeout = d
+	}
+}
+
+// WithSigningSecret signs every request with HMAC-SHA256, see SignatureHeader and TimestampHeader
+// Signed messages with streamed files are built in memory, since the signature covers the whole body.
+func WithSigningSecret(secret string) Option {
+	return func(s *Sender) {
+		s.signingSecret = secret
+	}
+}
+
+// WithRateLimits shares the rate limit state with other senders
+func WithRateLimits(l *RateLimits) Option {
+	return func(s *Sender) {
+		s.limits = l
+	}
+}
+
+// New creates a sender with DefaultRetryPolicy and a 10 second timeout
+func New(opts ...Option) *Sender {
+	s := &Sender{retry: DefaultRetryPolicy, timeout: DefaultTimeout}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.client == nil {
+		s.client = &http.Client{}
+	}
+	if s.limits == nil {
+		s.limits = &RateLimits{}
+	}
+	return s
+}
+
+// Result describes the delivery of a message
+type Result struct {
+	Body        []byte        // Body of Discord's response, the created message when Message.Wait is set
+	Attempts    int           // Number of requests sent, 0 when the message could not be built
+	RateLimited int           // Number of requests answered with HTTP 429
+	Latency     time.Duration // Duration of the last request
+}
+
+// Send posts the message, waiting while the webhook is rate limited
+// Rate limited requests (HTTP 429) are retried after the delay indicated by Discord,
+// transient failures (network errors, HTTP 500, 502, 503 and 504) according to the retry policy.
+func (s *Sender) Send(ctx context.Context, m *Message) (Result, error) {
+	var res Result
+	requestURL, err := m.RequestURL()
+	if err != nil {
+		return res, err
+	}
+	if len(m.Files) > MaxFiles {
+		return res, eris.Errorf("message has %d files, Discord accepts at most %d", len(m.Files), MaxFiles)
+	}
+	// Signing butuh seluruh body, jadi streaming hanya dipakai tanpa signing
+	getBody, raw, err := m.bodyFactory(s.signingSecret != "")
+	if err != nil {
+		return res, err
+	}
+	limiter := s.limits.limiter(m.WebhookURL)
+
+	retries, rateLimited := 0, 0
+	for {
+		// Tunggu jika webhook sedang terkena rate limit
+		if err := limiter.wait(ctx); err != nil {
+			return res, eris.Wrap(err, "discord webhook delivery interrupted")
+		}
+
+		start := time.Now()
+		res.Attempts++
+		reqCtx, cancel := s.requestContext(ctx)
+		respons, err := s.postOnce(reqCtx, m.method(), requestURL, getBody, raw, m.contentType())
+		if err != nil {
+			cancel()
+			res.Latency = time.Since(start)
+			if retries < s.retry.MaxRetries && isTransient(err) {
+				if sleep(ctx, s.retry.Delay(retries)) == nil {
+					retries++
+					continue
+				}
+			}
+			return res, err
+		}
+		retryAfter := limiter.update(respons)
+
+		var apiErr error
+		if respons.StatusCode >= 300 && respons.StatusCode != http.StatusTooManyRequests {
+			apiErr = APIError(respons)
+		} else if respons.StatusCode < 300 {
+			res.Body, _ = io.ReadAll(io.LimitReader(respons.Body, maxResponseBodySize))
+		}
+		io.Copy(io.Discard, respons.Body)
+		respons.Body.Close()
+		cancel()
+		res.Latency = time.Since(start)
+
+		if respons.StatusCode == http.StatusTooManyRequests {
+			res.RateLimited++
+			if rateLimited < maxRateLimitRetries {
+				rateLimited++
+				continue
+			}
+			return res, &ErrRateLimited{RetryAfter: retryAfter}
+		}
+		if apiErr != nil && retries < s.retry.MaxRetries && isTransient(apiErr) {
+			if sleep(ctx, s.retry.Delay(retries)) == nil {
+				retries++
+				continue
+			}
+		}
+		return res, apiErr
+	}
+}
+
+// Go sends the message in the background and calls done, if not nil, with the outcome
+// Use Flush to wait for all queued messages.
+func (s *Sender) Go(m *Message, done func(Result, error)) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		res, err := s.Send(context.Background(), m)
+		if done != nil {
+			done(res, err)
+		}
+	}()
+}
+
+// Flush blocks until all messages queued with Go have been sent or ctx is done
+func (s *Sender) Flush(ctx context.Context) error {
+	idle := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(idle)
+	}()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return eris.Wrap(ctx.Err(), "discord sender flush interrupted")
+	}
+}
+
+// Get fetches requestURL, the webhook or one of its messages, through Do within the
+// timeout. The body of the returned response has already been read (at most 64 KB) and
+// can still be read from the response, e.g. by APIError.
+func (s *Sender) Get(ctx context.Context, webhookURL, requestURL string) (*http.Response, []byte, error) {
+	ctx, cancel := s.requestContext(ctx)
+	defer cancel()
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	respons, err := s.Do(webhookURL, request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer respons.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(respons.Body, maxResponseBodySize))
+	if err != nil {
+		return nil, nil, err
+	}
+	respons.Body = io.NopCloser(bytes.NewReader(data))
+	return respons, data, nil
+}
+
+// Do sends a request without a body to the webhook, e.g. a GET of a sent message,
+// honoring and updating the webhook's rate limit. The request is signed when a signing
+// secret is configured. The caller closes the response body.
+func (s *Sender) Do(webhookURL string, request *http.Request) (*http.Response, error) {
+	limiter := s.limits.limiter(webhookURL)
+	if err := limiter.wait(request.Context()); err != nil {
+		return nil, eris.Wrap(err, "discord webhook request interrupted")
+	}
+	if s.signingSecret != "" {
+		signRequest(request, nil, s.signingSecret, time.Now())
+	}
+	respons, err := s.client.Do(request)
+	if err != nil {
+		return nil, err
+	}
+	limiter.update(respons)
+	return respons, nil
+}
+
+// RateLimited reports whether sends to the webhook are currently paused
+func (s *Sender) RateLimited(webhookURL string) bool {
+	return s.limits.Blocked(webhookURL)
+}
+
+// requestContext returns the context of a single request, bounded by the timeout
+func (s *Sender) requestContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if s.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, s.timeout)
+}
+
+// postOnce performs a single request with a fresh body
+func (s *Sender) postOnce(ctx context.Context, method, requestURL string, getBody func() (io.ReadCloser, error), raw []byte, contentType string) (*http.Response, error) {
+	body, err := getBody()
+	if err != nil {
+		return nil, err
+	}
+
+	request, err := http.NewRequestWithContext(ctx, method, requestURL, body)
+	if err != nil {
+		body.Close()
+		return nil, err
+	}
+	request.GetBody = getBody
+	if raw != nil {
+		request.ContentLength = int64(len(raw))
+	}
+	request.Header.Set("Content-Type", contentType)
+
+	if s.signingSecret != "" {
+		signRequest(request, raw, s.signingSecret, time.Now())
+	}
+
+	return s.client.Do(request)
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
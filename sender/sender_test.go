@@ -0,0 +1,31 @@
+package sender
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestGetKeepsBodyReadable(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("method = %s, want GET", r.Method)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"code": 10015, "message": "Unknown Webhook"}`))
+	}))
+	defer srv.Close()
+
+	respons, body, err := New().Get(context.Background(), srv.URL, srv.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(body) == 0 {
+		t.Fatal("Get returned no body")
+	}
+	apiErr := APIError(respons)
+	if apiErr.Code != 10015 || apiErr.Message != "Unknown Webhook" {
+		t.Errorf("APIError() = %+v, want the error of the body", apiErr)
+	}
+}
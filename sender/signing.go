@@ -0,0 +1,63 @@
+package sender
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// SignatureHeader is the header carrying the hex encoded HMAC-SHA256
+	// signature of the outgoing webhook request
+	SignatureHeader = "X-Discordrus-Signature"
+
+	// TimestampHeader is the header carrying the unix timestamp (seconds)
+	// that was included in the signature
+	TimestampHeader = "X-Discordrus-Timestamp"
+
+	// DefaultSignatureTolerance is how far the timestamp of a request checked with
+	// VerifySignature may be from the current time
+	DefaultSignatureTolerance = 5 * time.Minute
+)
+
+// Sign computes the signature of a webhook body the same way the sender does:
+// hex(HMAC-SHA256(secret, timestamp + "." + body)).
+// Proxies can use it to verify that a request originated from an authorized service.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether signature is a valid signature of body for the given
+// secret and timestamp, and the timestamp is at most DefaultSignatureTolerance from now,
+// so a captured request cannot be replayed later
+func VerifySignature(secret string, timestamp int64, body []byte, signature string) bool {
+	return VerifySignatureAt(secret, timestamp, body, signature, time.Now(), DefaultSignatureTolerance)
+}
+
+// VerifySignatureAt reports whether signature is a valid signature of body for the given
+// secret and timestamp, and the timestamp is at most maxSkew before or after now.
+// A maxSkew of 0 only checks the signature.
+func VerifySignatureAt(secret string, timestamp int64, body []byte, signature string, now time.Time, maxSkew time.Duration) bool {
+	if maxSkew > 0 {
+		skew := now.Sub(time.Unix(timestamp, 0))
+		if skew > maxSkew || skew < -maxSkew {
+			return false
+		}
+	}
+	expected := Sign(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// signRequest adds the timestamp and signature headers to the request
+func signRequest(request *http.Request, body []byte, secret string, now time.Time) {
+	ts := now.Unix()
+	request.Header.Set(TimestampHeader, strconv.FormatInt(ts, 10))
+	request.Header.Set(SignatureHeader, Sign(secret, ts, body))
+}
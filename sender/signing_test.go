@@ -0,0 +1,47 @@
+package sender
+
+import (
+	"testing"
+	"time"
+)
+
+func TestVerifySignatureAt(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	body := []byte(`{"content":"hi"}`)
+	sign := func(ts time.Time) (int64, string) { return ts.Unix(), Sign("secret", ts.Unix(), body) }
+
+	tests := []struct {
+		name    string
+		signed  time.Time
+		secret  string
+		maxSkew time.Duration
+		want    bool
+	}{
+		{"fresh", now.Add(-time.Minute), "secret", 5 * time.Minute, true},
+		{"slightly ahead", now.Add(30 * time.Second), "secret", 5 * time.Minute, true},
+		{"stale", now.Add(-10 * time.Minute), "secret", 5 * time.Minute, false},
+		{"future", now.Add(10 * time.Minute), "secret", 5 * time.Minute, false},
+		{"wrong secret", now, "other", 5 * time.Minute, false},
+		{"no tolerance", now.Add(-24 * time.Hour), "secret", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts, sig := sign(tt.signed)
+			if got := VerifySignatureAt(tt.secret, ts, body, sig, now, tt.maxSkew); got != tt.want {
+				t.Errorf("VerifySignatureAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifySignatureRejectsReplay(t *testing.T) {
+	body := []byte("payload")
+	old := time.Now().Add(-time.Hour).Unix()
+	if VerifySignature("secret", old, body, Sign("secret", old, body)) {
+		t.Error("VerifySignature accepted a timestamp an hour old")
+	}
+	ts := time.Now().Unix()
+	if !VerifySignature("secret", ts, body, Sign("secret", ts, body)) {
+		t.Error("VerifySignature rejected a fresh signature")
+	}
+}
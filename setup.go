@@ -0,0 +1,440 @@
+package discordrus
+
+import (
+	"net/netip"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"github.com/sirupsen/logrus"
+)
+
+// Config is the complete configuration of a hook created with NewHookFromConfig
+// Zero values keep the defaults of NewHook. Settings without a field here,
+// e.g. callbacks or a custom formatter, are passed as Options.
+type Config struct {
+	WebhookURL    string
+	Levels        []logrus.Level // Default: DefaultLevels
+	Username      string
+	AvatarURL     string
+	SigningSecret string
+
+	AppName     string
+	AppVersion  string
+	Environment string
+	Hostname    string
+
+	Synchronous    bool // Deliver before Fire returns, see WithSynchronous
+	Workers        int
+	QueueSize      int
+	OverflowPolicy OverflowPolicy
+	Timeout        time.Duration // Default: DefaultTimeout
+	Retry          *RetryPolicy  // Default: DefaultRetryPolicy
+
+	ThreadID       string
+	AppliedTags    []string
+	CategoryField  string
+	CategoryRoutes map[string]string
+	LevelRoutes    map[logrus.Level]string
+
+	SampleRates      map[logrus.Level]int
+	FieldOrder       []string
+	ExcludedFields   []string
+	RedactedHeaders  []string // Added to the default redaction rules
+	RedactedBodyKeys []string // Added to the default redaction rules
+	TrustedProxies   []netip.Prefix
+	ClientInfo       bool
+	CurlCommand      bool
+	HARAttachment    bool
+	GoroutineDump    bool
+	SystemInfo       bool
+	TraceIDField     string
+	StackTrace       bool
+	PayloadVersion   PayloadVersion
+	ComponentsV2     bool
+	Labels           *Labels // Default: DefaultLabels
+
+	BatchMaxEntries     int
+	BatchWindow         time.Duration
+	CoalesceWindow      time.Duration
+	DedupWindow         time.Duration
+	DigestInterval      time.Duration
+	HealthProbeInterval time.Duration
+
+	ExportDir         string
+	MaxAttachmentSize int64
+	MaxBodySize       int
+	PersistentQueue   string
+
+	// Options are applied after the fields above
+	Options []Option
+}
+
+// NewHookFromConfig creates a new Discord webhook hook from a config struct, e.g. one
+// loaded from a configuration file
+func NewHookFromConfig(cfg Config) *Hook {
+	return NewHook(cfg.WebhookURL, cfg.options()...)
+}
+
+// options converts the config into Options
+func (cfg Config) options() []Option {
+	opts := []Option{
+		WithLevels(cfg.Levels...),
+		WithUsername(cfg.Username),
+		WithAvatarURL(cfg.AvatarURL),
+		WithSigningSecret(cfg.SigningSecret),
+		WithAppName(cfg.AppName),
+		WithAppVersion(cfg.AppVersion),
+		WithEnvironment(cfg.Environment),
+		WithPayloadVersion(cfg.PayloadVersion),
+	}
+	if cfg.Hostname != "" {
+		opts = append(opts, WithHostname(cfg.Hostname))
+	}
+	if cfg.Synchronous {
+		opts = append(opts, WithSynchronous())
+	}
+	if cfg.Workers > 0 || cfg.QueueSize > 0 || cfg.OverflowPolicy != OverflowDropNewest {
+		opts = append(opts, WithWorkerPool(cfg.Workers, cfg.QueueSize, cfg.OverflowPolicy))
+	}
+	if cfg.Timeout > 0 {
+		opts = append(opts, WithTimeout(cfg.Timeout))
+	}
+	if cfg.Retry != nil {
+		opts = append(opts, WithRetryPolicy(*cfg.Retry))
+	}
+	if cfg.ThreadID != "" {
+		opts = append(opts, WithThreadID(cfg.ThreadID))
+	}
+	if len(cfg.AppliedTags) > 0 {
+		opts = append(opts, WithAppliedTags(cfg.AppliedTags...))
+	}
+	if cfg.CategoryField != "" {
+		opts = append(opts, WithCategoryField(cfg.CategoryField))
+	}
+	if len(cfg.CategoryRoutes) > 0 {
+		opts = append(opts, WithCategoryRoutes(cfg.CategoryRoutes))
+	}
+	if len(cfg.LevelRoutes) > 0 {
+		opts = append(opts, WithLevelRoutes(cfg.LevelRoutes))
+	}
+	for level, rate := range cfg.SampleRates {
+		opts = append(opts, WithSampling(rate, level))
+	}
+	if len(cfg.FieldOrder) > 0 {
+		opts = append(opts, WithFieldOrder(cfg.FieldOrder...))
+	}
+	if len(cfg.ExcludedFields) > 0 {
+		opts = append(opts, WithExcludedFields(cfg.ExcludedFields...))
+	}
+	if len(cfg.RedactedHeaders) > 0 {
+		opts = append(opts, WithRedactedHeaders(cfg.RedactedHeaders...))
+	}
+	if len(cfg.RedactedBodyKeys) > 0 {
+		opts = append(opts, WithRedactedBodyKeys(cfg.RedactedBodyKeys...))
+	}
+	if len(cfg.TrustedProxies) > 0 {
+		opts = append(opts, WithTrustedProxies(cfg.TrustedProxies...))
+	}
+	if cfg.ClientInfo {
+		opts = append(opts, WithClientInfo())
+	}
+	if cfg.CurlCommand {
+		opts = append(opts, WithCurlCommand())
+	}
+	if cfg.HARAttachment {
+		opts = append(opts, WithHARAttachment())
+	}
+	if cfg.GoroutineDump {
+		opts = append(opts, WithGoroutineDump())
+	}
+	if cfg.SystemInfo {
+		opts = append(opts, WithSystemInfo())
+	}
+	if cfg.TraceIDField != "" {
+		opts = append(opts, WithTraceIDField(cfg.TraceIDField))
+	}
+	if cfg.StackTrace {
+		opts = append(opts, WithStackTrace())
+	}
+	if cfg.BatchMaxEntries > 0 || cfg.BatchWindow > 0 {
+		opts = append(opts, WithBatching(cfg.BatchMaxEntries, cfg.BatchWindow))
+	}
+	if cfg.CoalesceWindow > 0 {
+		opts = append(opts, WithBurstCoalescing(cfg.CoalesceWindow))
+	}
+	if cfg.DedupWindow > 0 {
+		opts = append(opts, WithDeduplication(cfg.DedupWindow, nil))
+	}
+	if cfg.DigestInterval > 0 {
+		opts = append(opts, WithDigest(cfg.DigestInterval))
+	}
+	if cfg.HealthProbeInterval > 0 {
+		opts = append(opts, WithHealthProbe(cfg.HealthProbeInterval, nil))
+	}
+	if cfg.ExportDir != "" {
+		opts = append(opts, WithExportDir(cfg.ExportDir))
+	}
+	if cfg.MaxAttachmentSize > 0 {
+		opts = append(opts, WithMaxAttachmentSize(cfg.MaxAttachmentSize))
+	}
+	if cfg.ComponentsV2 {
+		opts = append(opts, WithComponentsV2())
+	}
+	if cfg.Labels != nil {
+		opts = append(opts, WithLabels(*cfg.Labels))
+	}
+	if cfg.MaxBodySize > 0 {
+		opts = append(opts, WithMaxBodySize(cfg.MaxBodySize))
+	}
+	if cfg.PersistentQueue != "" {
+		opts = append(opts, WithPersistentQueue(cfg.PersistentQueue))
+	}
+	return append(opts, cfg.Options...)
+}
+
+// NewHookFromEnv creates a new Discord webhook hook configured by DISCORDRUS_* environment
+// variables, see ConfigFromEnv. opts are applied after the environment, e.g. OnError
+// callbacks that cannot be set from the environment.
+func NewHookFromEnv(opts ...Option) (*Hook, error) {
+	cfg, err := ConfigFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	cfg.Options = append(cfg.Options, opts...)
+	return NewHookFromConfig(cfg), nil
+}
+
+// ConfigFromEnv reads the hook configuration from the environment. DISCORDRUS_WEBHOOK_URL
+// is required. DISCORDRUS_LEVELS takes a LevelsFromString specification ("warn+" or
+// "error,info"). Lists are comma separated, routes and sample rates are
+// key=value lists ("error=https://…,warn=https://…", "info=10"), durations use Go syntax
+// ("2s", "1m") and booleans strconv syntax ("true", "1"):
+//
+//	DISCORDRUS_WEBHOOK_URL, DISCORDRUS_LEVELS, DISCORDRUS_USERNAME, DISCORDRUS_AVATAR_URL,
+//	DISCORDRUS_SIGNING_SECRET, DISCORDRUS_APP_NAME, DISCORDRUS_APP_VERSION,
+//	DISCORDRUS_ENVIRONMENT, DISCORDRUS_HOSTNAME, DISCORDRUS_SYNCHRONOUS, DISCORDRUS_WORKERS,
+//	DISCORDRUS_QUEUE_SIZE, DISCORDRUS_OVERFLOW_POLICY (drop_newest, drop_oldest, block),
+//	DISCORDRUS_TIMEOUT, DISCORDRUS_MAX_RETRIES, DISCORDRUS_THREAD_ID, DISCORDRUS_APPLIED_TAGS,
+//	DISCORDRUS_CATEGORY_FIELD, DISCORDRUS_CATEGORY_ROUTES, DISCORDRUS_LEVEL_ROUTES,
+//	DISCORDRUS_SAMPLE_RATES, DISCORDRUS_FIELD_ORDER, DISCORDRUS_EXCLUDED_FIELDS,
+//	DISCORDRUS_REDACTED_HEADERS, DISCORDRUS_REDACTED_BODY_KEYS, DISCORDRUS_TRUSTED_PROXIES,
+//	DISCORDRUS_CLIENT_INFO, DISCORDRUS_CURL_COMMAND, DISCORDRUS_HAR_ATTACHMENT,
+//	DISCORDRUS_GOROUTINE_DUMP, DISCORDRUS_SYSTEM_INFO, DISCORDRUS_TRACE_ID_FIELD,
+//	DISCORDRUS_STACK_TRACE,
+//	DISCORDRUS_PAYLOAD_VERSION (v1, v2), DISCORDRUS_COMPONENTS_V2,
+//	DISCORDRUS_BATCH_MAX_ENTRIES, DISCORDRUS_BATCH_WINDOW, DISCORDRUS_COALESCE_WINDOW,
+//	DISCORDRUS_DEDUP_WINDOW, DISCORDRUS_DIGEST_INTERVAL, DISCORDRUS_HEALTH_PROBE_INTERVAL,
+//	DISCORDRUS_EXPORT_DIR, DISCORDRUS_MAX_ATTACHMENT_SIZE, DISCORDRUS_MAX_BODY_SIZE,
+//	DISCORDRUS_PERSISTENT_QUEUE
+func ConfigFromEnv() (Config, error) {
+	e := envReader{}
+	cfg := Config{
+		WebhookURL:       e.text("DISCORDRUS_WEBHOOK_URL"),
+		Levels:           e.levels("DISCORDRUS_LEVELS"),
+		Username:         e.text("DISCORDRUS_USERNAME"),
+		AvatarURL:        e.text("DISCORDRUS_AVATAR_URL"),
+		SigningSecret:    e.text("DISCORDRUS_SIGNING_SECRET"),
+		AppName:          e.text("DISCORDRUS_APP_NAME"),
+		AppVersion:       e.text("DISCORDRUS_APP_VERSION"),
+		Environment:      e.text("DISCORDRUS_ENVIRONMENT"),
+		Hostname:         e.text("DISCORDRUS_HOSTNAME"),
+		Synchronous:      e.boolean("DISCORDRUS_SYNCHRONOUS"),
+		Workers:          e.integer("DISCORDRUS_WORKERS"),
+		QueueSize:        e.integer("DISCORDRUS_QUEUE_SIZE"),
+		OverflowPolicy:   e.overflowPolicy("DISCORDRUS_OVERFLOW_POLICY"),
+		Timeout:          e.duration("DISCORDRUS_TIMEOUT"),
+		ThreadID:         e.text("DISCORDRUS_THREAD_ID"),
+		AppliedTags:      e.list("DISCORDRUS_APPLIED_TAGS"),
+		CategoryField:    e.text("DISCORDRUS_CATEGORY_FIELD"),
+		CategoryRoutes:   e.pairs("DISCORDRUS_CATEGORY_ROUTES"),
+		FieldOrder:       e.list("DISCORDRUS_FIELD_ORDER"),
+		ExcludedFields:   e.list("DISCORDRUS_EXCLUDED_FIELDS"),
+		RedactedHeaders:  e.list("DISCORDRUS_REDACTED_HEADERS"),
+		RedactedBodyKeys: e.list("DISCORDRUS_REDACTED_BODY_KEYS"),
+		ClientInfo:       e.boolean("DISCORDRUS_CLIENT_INFO"),
+		CurlCommand:      e.boolean("DISCORDRUS_CURL_COMMAND"),
+		HARAttachment:    e.boolean("DISCORDRUS_HAR_ATTACHMENT"),
+		GoroutineDump:    e.boolean("DISCORDRUS_GOROUTINE_DUMP"),
+		SystemInfo:       e.boolean("DISCORDRUS_SYSTEM_INFO"),
+		TraceIDField:     e.text("DISCORDRUS_TRACE_ID_FIELD"),
+		StackTrace:       e.boolean("DISCORDRUS_STACK_TRACE"),
+		PayloadVersion:   e.payloadVersion("DISCORDRUS_PAYLOAD_VERSION"),
+		ComponentsV2:     e.boolean("DISCORDRUS_COMPONENTS_V2"),
+
+		BatchMaxEntries:     e.integer("DISCORDRUS_BATCH_MAX_ENTRIES"),
+		BatchWindow:         e.duration("DISCORDRUS_BATCH_WINDOW"),
+		CoalesceWindow:      e.duration("DISCORDRUS_COALESCE_WINDOW"),
+		DedupWindow:         e.duration("DISCORDRUS_DEDUP_WINDOW"),
+		DigestInterval:      e.duration("DISCORDRUS_DIGEST_INTERVAL"),
+		HealthProbeInterval: e.duration("DISCORDRUS_HEALTH_PROBE_INTERVAL"),
+
+		ExportDir:         e.text("DISCORDRUS_EXPORT_DIR"),
+		MaxAttachmentSize: int64(e.integer("DISCORDRUS_MAX_ATTACHMENT_SIZE")),
+		MaxBodySize:       e.integer("DISCORDRUS_MAX_BODY_SIZE"),
+		PersistentQueue:   e.text("DISCORDRUS_PERSISTENT_QUEUE"),
+	}
+	if e.has("DISCORDRUS_MAX_RETRIES") {
+		retry := DefaultRetryPolicy
+		retry.MaxRetries = e.integer("DISCORDRUS_MAX_RETRIES")
+		cfg.Retry = &retry
+	}
+	for level, route := range e.pairs("DISCORDRUS_LEVEL_ROUTES") {
+		if cfg.LevelRoutes == nil {
+			cfg.LevelRoutes = make(map[logrus.Level]string)
+		}
+		cfg.LevelRoutes[e.level("DISCORDRUS_LEVEL_ROUTES", level)] = route
+	}
+	for level, rate := range e.pairs("DISCORDRUS_SAMPLE_RATES") {
+		if cfg.SampleRates == nil {
+			cfg.SampleRates = make(map[logrus.Level]int)
+		}
+		n, err := strconv.Atoi(rate)
+		if err != nil {
+			e.fail("DISCORDRUS_SAMPLE_RATES", err)
+		}
+		cfg.SampleRates[e.level("DISCORDRUS_SAMPLE_RATES", level)] = n
+	}
+	for _, prefix := range e.list("DISCORDRUS_TRUSTED_PROXIES") {
+		p, err := netip.ParsePrefix(prefix)
+		if err != nil {
+			e.fail("DISCORDRUS_TRUSTED_PROXIES", err)
+			continue
+		}
+		cfg.TrustedProxies = append(cfg.TrustedProxies, p)
+	}
+
+	if e.err != nil {
+		return Config{}, e.err
+	}
+	if cfg.WebhookURL == "" {
+		return Config{}, eris.Wrap(ErrWebhookEmpty, "DISCORDRUS_WEBHOOK_URL is not set")
+	}
+	return cfg, nil
+}
+
+// envReader reads environment variables, keeping the first parse error
+type envReader struct {
+	err error
+}
+
+func (e *envReader) has(name string) bool {
+	return strings.TrimSpace(os.Getenv(name)) != ""
+}
+
+func (e *envReader) text(name string) string {
+	return strings.TrimSpace(os.Getenv(name))
+}
+
+func (e *envReader) fail(name string, err error) {
+	if e.err == nil {
+		e.err = eris.Wrapf(err, "invalid %s", name)
+	}
+}
+
+func (e *envReader) list(name string) []string {
+	var values []string
+	for _, v := range strings.Split(e.text(name), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+// pairs reads a key=value list, values may contain "=" themselves
+func (e *envReader) pairs(name string) map[string]string {
+	items := e.list(name)
+	if len(items) == 0 {
+		return nil
+	}
+	values := make(map[string]string, len(items))
+	for _, item := range items {
+		key, value, ok := strings.Cut(item, "=")
+		if !ok {
+			e.fail(name, eris.Errorf("%q is not a key=value pair", item))
+			continue
+		}
+		values[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return values
+}
+
+func (e *envReader) boolean(name string) bool {
+	if !e.has(name) {
+		return false
+	}
+	v, err := strconv.ParseBool(e.text(name))
+	if err != nil {
+		e.fail(name, err)
+	}
+	return v
+}
+
+func (e *envReader) integer(name string) int {
+	if !e.has(name) {
+		return 0
+	}
+	v, err := strconv.Atoi(e.text(name))
+	if err != nil {
+		e.fail(name, err)
+	}
+	return v
+}
+
+func (e *envReader) duration(name string) time.Duration {
+	if !e.has(name) {
+		return 0
+	}
+	v, err := time.ParseDuration(e.text(name))
+	if err != nil {
+		e.fail(name, err)
+	}
+	return v
+}
+
+func (e *envReader) level(name, text string) logrus.Level {
+	level, err := logrus.ParseLevel(text)
+	if err != nil {
+		e.fail(name, err)
+	}
+	return level
+}
+
+// levels reads a level specification, see LevelsFromString
+func (e *envReader) levels(name string) []logrus.Level {
+	if !e.has(name) {
+		return nil
+	}
+	levels, err := LevelsFromString(e.text(name))
+	if err != nil {
+		e.fail(name, err)
+	}
+	return levels
+}
+
+func (e *envReader) overflowPolicy(name string) OverflowPolicy {
+	switch e.text(name) {
+	case "", "drop_newest":
+		return OverflowDropNewest
+	case "drop_oldest":
+		return OverflowDropOldest
+	case "block":
+		return OverflowBlock
+	}
+	e.fail(name, eris.Errorf("unknown overflow policy %q", e.text(name)))
+	return OverflowDropNewest
+}
+
+func (e *envReader) payloadVersion(name string) PayloadVersion {
+	switch strings.ToLower(e.text(name)) {
+	case "", "v1", "1":
+		return PayloadV1
+	case "v2", "2":
+		return PayloadV2
+	}
+	e.fail(name, eris.Errorf("unknown payload version %q", e.text(name)))
+	return PayloadV1
+}
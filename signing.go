@@ -0,0 +1,42 @@
+package discordrus
+
+import (
+	"time"
+
+	"github.com/murbagus/discordrus/sender"
+)
+
+const (
+	// SignatureHeader is the header carrying the hex encoded HMAC-SHA256
+	// signature of the outgoing webhook request
+	SignatureHeader = sender.SignatureHeader
+
+	// TimestampHeader is the header carrying the unix timestamp (seconds)
+	// that was included in the signature
+	TimestampHeader = sender.TimestampHeader
+
+	// DefaultSignatureTolerance is how far the timestamp of a request checked with
+	// VerifySignature may be from the current time
+	DefaultSignatureTolerance = sender.DefaultSignatureTolerance
+)
+
+// Sign computes the signature of a webhook body the same way the hook does:
+// hex(HMAC-SHA256(secret, timestamp + "." + body)).
+// Proxies can use it to verify that a request originated from an authorized service.
+func Sign(secret string, timestamp int64, body []byte) string {
+	return sender.Sign(secret, timestamp, body)
+}
+
+// VerifySignature reports whether signature is a valid signature of body for the given
+// secret and timestamp, and the timestamp is at most DefaultSignatureTolerance from now,
+// so a captured request cannot be replayed later
+func VerifySignature(secret string, timestamp int64, body []byte, signature string) bool {
+	return sender.VerifySignature(secret, timestamp, body, signature)
+}
+
+// VerifySignatureAt reports whether signature is a valid signature of body for the given
+// secret and timestamp, and the timestamp is at most maxSkew before or after now.
+// A maxSkew of 0 only checks the signature.
+func VerifySignatureAt(secret string, timestamp int64, body []byte, signature string, now time.Time, maxSkew time.Duration) bool {
+	return sender.VerifySignatureAt(secret, timestamp, body, signature, now, maxSkew)
+}
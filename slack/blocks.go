@@ -0,0 +1,234 @@
+package slack
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/murbagus/discordrus"
+	"github.com/rotisserie/eris"
+)
+
+// Block Kit limits, see https://docs.slack.dev/reference/block-kit/blocks
+const (
+	maxBlocks          = 50
+	maxHeaderLength    = 150
+	maxTextLength      = 3000
+	maxFieldLength     = 2000
+	maxFieldsPerBlock  = 10
+	maxFallbackLength  = 150
+	maxShortFieldValue = 60
+)
+
+var (
+	boldMarkdown = regexp.MustCompile(`\*\*(.+?)\*\*`)
+	// Slack tidak mengenal bahasa pada code block, baris ```json cukup menjadi ```
+	codeLanguage = regexp.MustCompile("```[A-Za-z0-9_+-]+\n")
+)
+
+// message is an incoming webhook message, see https://docs.slack.dev/messaging/sending-messages-using-incoming-webhooks
+type message struct {
+	Text        string       `json:"text"`
+	Username    string       `json:"username,omitempty"`
+	IconURL     string       `json:"icon_url,omitempty"`
+	Blocks      []block      `json:"blocks,omitempty"`
+	Attachments []attachment `json:"attachments,omitempty"`
+}
+
+// attachment is a secondary block group, used for the colored bar of an entry
+type attachment struct {
+	Color  string  `json:"color,omitempty"`
+	Blocks []block `json:"blocks"`
+}
+
+// block is a Block Kit layout block; the fields used depend on Type
+type block struct {
+	Type     string `json:"type"`
+	Text     *text  `json:"text,omitempty"`     // Header, section
+	Fields   []text `json:"fields,omitempty"`   // Section
+	Elements []text `json:"elements,omitempty"` // Context
+}
+
+// text is a Block Kit text object
+type text struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Render converts a Discord webhook payload into a Slack incoming webhook message. Every
+// entry becomes an attachment in the level color, with embed titles as headers, fields as
+// two-column section fields where they are short and footers as context. files are the
+// names of the attachments of the message, which incoming webhooks cannot upload; they
+// are listed at the end of the message.
+func Render(payload []byte, files []string) ([]byte, error) {
+	var p discordrus.WebhookPayload
+	if err := json.Unmarshal(payload, &p); err != nil {
+		return nil, eris.Wrap(err, "failed to decode Discord webhook payload")
+	}
+
+	msg := message{Username: p.Username, IconURL: p.AvatarURL}
+	budget := maxBlocks
+	if len(files) > 0 {
+		budget--
+	}
+	if p.Content != "" {
+		msg.Blocks = append(msg.Blocks, section(p.Content))
+		budget--
+	}
+
+	// Setiap embed dengan timestamp (embed level) memulai attachment baru
+	var color int
+	for _, e := range p.Embeds {
+		blocks := embedBlocks(e)
+		current := len(msg.Attachments) - 1
+		newAttachment := current < 0 || e.Timestamp != "" || e.Color != color
+		if !newAttachment {
+			blocks = append([]block{{Type: "divider"}}, blocks...)
+		}
+		if len(blocks) > budget {
+			break
+		}
+		budget -= len(blocks)
+		if newAttachment {
+			msg.Attachments = append(msg.Attachments, attachment{Color: fmt.Sprintf("#%06x", e.Color), Blocks: blocks})
+			color = e.Color
+			continue
+		}
+		msg.Attachments[current].Blocks = append(msg.Attachments[current].Blocks, blocks...)
+	}
+
+	if len(files) > 0 {
+		note := block{Type: "context", Elements: []text{mrkdwn("Attachments not delivered to Slack: "+strings.Join(files, ", "), maxTextLength)}}
+		if last := len(msg.Attachments) - 1; last >= 0 {
+			msg.Attachments[last].Blocks = append(msg.Attachments[last].Blocks, note)
+		} else {
+			msg.Blocks = append(msg.Blocks, note)
+		}
+	}
+	msg.Text = fallbackText(p)
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return nil, eris.Wrap(err, "failed to marshal Slack webhook payload")
+	}
+	return data, nil
+}
+
+// embedBlocks renders an embed: the title as header (level embeds) or bold section,
+// the description, the fields and the footer with the timestamp
+func embedBlocks(e discordrus.Embed) []block {
+	var blocks []block
+
+	description := e.Description
+	switch {
+	case e.Title != "" && e.Timestamp != "" && e.URL == "":
+		blocks = append(blocks, block{Type: "header", Text: &text{Type: "plain_text", Text: cut(e.Title, maxHeaderLength)}})
+	case e.Title != "" && e.URL != "":
+		description = "*<" + e.URL + "|" + escape(e.Title) + ">*\n" + convertMarkdown(description)
+		blocks = append(blocks, block{Type: "section", Text: &text{Type: "mrkdwn", Text: cut(description, maxTextLength)}})
+		description = ""
+	case e.Title != "":
+		description = strings.TrimSpace("**" + e.Title + "**\n" + description)
+	}
+	if strings.TrimSpace(description) != "" {
+		blocks = append(blocks, section(description))
+	}
+
+	// Field pendek digabung menjadi kolom, field panjang menjadi section sendiri
+	var short []text
+	flush := func() {
+		if len(short) > 0 {
+			blocks = append(blocks, block{Type: "section", Fields: short})
+			short = nil
+		}
+	}
+	for _, f := range e.Fields {
+		if isShort(f) {
+			short = append(short, mrkdwn("**"+f.Name+"**\n"+f.Value, maxFieldLength))
+			if len(short) == maxFieldsPerBlock {
+				flush()
+			}
+			continue
+		}
+		flush()
+		blocks = append(blocks, section("**"+f.Name+"**\n"+f.Value))
+	}
+	flush()
+
+	var footer []string
+	if e.Footer != nil && e.Footer.Text != "" {
+		footer = append(footer, escape(e.Footer.Text))
+	}
+	if t, err := time.Parse(time.RFC3339, e.Timestamp); err == nil {
+		footer = append(footer, fmt.Sprintf("<!date^%d^{date_short_pretty} {time_secs}|%s>", t.Unix(), t.UTC().Format(time.RFC3339)))
+	}
+	if len(footer) > 0 {
+		blocks = append(blocks, block{Type: "context", Elements: []text{{Type: "mrkdwn", Text: cut(strings.Join(footer, " · "), maxTextLength)}}})
+	}
+	return blocks
+}
+
+// isShort reports whether a field fits a column: inline fields and single-line values
+func isShort(f discordrus.EmbedField) bool {
+	value := strings.TrimSpace(f.Value)
+	return f.Inline || len([]rune(value)) <= maxShortFieldValue && !strings.Contains(value, "\n")
+}
+
+// section returns a mrkdwn section of Discord markdown
+func section(s string) block {
+	t := mrkdwn(s, maxTextLength)
+	return block{Type: "section", Text: &t}
+}
+
+// mrkdwn converts Discord markdown to a Slack mrkdwn text of at most n characters
+func mrkdwn(s string, n int) text {
+	return text{Type: "mrkdwn", Text: cut(convertMarkdown(s), n)}
+}
+
+// convertMarkdown rewrites Discord markdown to Slack mrkdwn: bold uses single asterisks
+// and code blocks carry no language
+func convertMarkdown(s string) string {
+	s = escape(s)
+	s = codeLanguage.ReplaceAllString(s, "```\n")
+	return boldMarkdown.ReplaceAllString(s, "*$1*")
+}
+
+// escape escapes the control characters of Slack mrkdwn
+func escape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+// cut shortens s to n characters, closing a code block it cut open
+func cut(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	s = string(runes[:n-5]) + "…"
+	if strings.Count(s, "```")%2 == 1 {
+		s += "\n```"
+	}
+	return s
+}
+
+// fallbackText returns the plain text shown in notifications: the content, or the
+// title and description of the first embed
+func fallbackText(p discordrus.WebhookPayload) string {
+	if p.Content != "" {
+		return cut(p.Content, maxFallbackLength)
+	}
+	if len(p.Embeds) == 0 {
+		return "discordrus"
+	}
+	e := p.Embeds[0]
+	s := e.Title
+	if d := strings.Trim(strings.TrimSpace(e.Description), "`"); d != "" {
+		if s != "" {
+			s += ": "
+		}
+		s += strings.TrimSpace(strings.SplitN(d, "\n", 2)[0])
+	}
+	return cut(s, maxFallbackLength)
+}
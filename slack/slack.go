@@ -0,0 +1,214 @@
+// Package slack delivers discordrus messages to Slack incoming webhooks. The Discord
+// embeds built by the hook are rendered as Block Kit blocks, so the same hook
+// configuration (levels, routes, redaction, formatter) can target Slack channels:
+//
+//	hook := discordrus.NewHook(slackWebhookURL, discordrus.WithSender(slack.New()))
+//
+// Incoming webhooks cannot upload files or edit messages: attachments are listed by name
+// and edits of sent messages are rejected.
+package slack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/murbagus/discordrus/sender"
+	"github.com/rotisserie/eris"
+)
+
+const (
+	// maxRateLimitRetries is how many times a rate limited message is re-sent before giving up
+	maxRateLimitRetries = 5
+
+	// maxRateLimitDelay caps the delay taken from Slack's Retry-After header
+	maxRateLimitDelay = time.Minute
+
+	// maxResponseBodySize caps how much of Slack's response is read
+	maxResponseBodySize = 4096
+)
+
+// ErrEditUnsupported is returned for messages editing a sent message, which incoming
+// webhooks cannot do
+var ErrEditUnsupported = eris.New("Slack incoming webhooks cannot edit messages")
+
+// ErrSlackAPI is returned when Slack answers with a non-success status code
+type ErrSlackAPI struct {
+	StatusCode int    // HTTP status code of the response
+	Message    string // Slack error, e.g. "invalid_payload" or "no_service"
+}
+
+func (e *ErrSlackAPI) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("Failed to post to Slack webhook: %d %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("Failed to post to Slack webhook: %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Sender posts messages to Slack incoming webhooks, see discordrus.WithSender
+// A Sender is safe for concurrent use.
+type Sender struct {
+	client  *http.Client
+	retry   sender.RetryPolicy
+	timeout time.Duration
+}
+
+// Option configures a Sender
+type Option func(*Sender)
+
+// WithHTTPClient sets the client used for all requests (default: a new http.Client)
+func WithHTTPClient(c *http.Client) Option {
+	return func(s *Sender) {
+		s.client = c
+	}
+}
+
+// WithTimeout sets the time a single request may take, 0 disables the timeout
+func WithTimeout(d time.Duration) Option {
+	return func(s *Sender) {
+		s.timeout = d
+	}
+}
+
+// WithRetryPolicy sets the retry policy for network errors and HTTP 500, 502, 503 and 504
+// responses. Rate limited requests (HTTP 429) are retried after the delay given by Slack.
+func WithRetryPolicy(p sender.RetryPolicy) Option {
+	return func(s *Sender) {
+		s.retry = p
+	}
+}
+
+// New creates a Slack sender with sender.DefaultRetryPolicy and sender.DefaultTimeout
+func New(opts ...Option) *Sender {
+	s := &Sender{retry: sender.DefaultRetryPolicy, timeout: sender.DefaultTimeout}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if s.client == nil {
+		s.client = &http.Client{}
+	}
+	return s
+}
+
+// Send renders the Discord payload of m as a Block Kit message and posts it to
+// m.WebhookURL, a Slack incoming webhook
+func (s *Sender) Send(ctx context.Context, m *sender.Message) (sender.Result, error) {
+	var res sender.Result
+	if m.EditID != "" {
+		return res, ErrEditUnsupported
+	}
+	files := make([]string, len(m.Files))
+	for i, f := range m.Files {
+		files[i] = f.Name
+	}
+	body, err := Render(m.Payload, files)
+	if err != nil {
+		return res, err
+	}
+
+	retries, rateLimited := 0, 0
+	for {
+		start := time.Now()
+		res.Attempts++
+		respons, data, err := s.postOnce(ctx, m.WebhookURL, body)
+		res.Latency = time.Since(start)
+		if err != nil {
+			if retries < s.retry.MaxRetries && transient(err) && sleep(ctx, s.retry.Delay(retries)) == nil {
+				retries++
+				continue
+			}
+			return res, err
+		}
+
+		switch {
+		case respons.StatusCode == http.StatusTooManyRequests:
+			res.RateLimited++
+			delay := retryAfter(respons)
+			if rateLimited < maxRateLimitRetries && sleep(ctx, delay) == nil {
+				rateLimited++
+				continue
+			}
+			return res, &sender.ErrRateLimited{RetryAfter: delay}
+		case respons.StatusCode >= 300:
+			apiErr := &ErrSlackAPI{StatusCode: respons.StatusCode, Message: strings.TrimSpace(string(data))}
+			if retries < s.retry.MaxRetries && transient(apiErr) && sleep(ctx, s.retry.Delay(retries)) == nil {
+				retries++
+				continue
+			}
+			return res, apiErr
+		}
+		res.Body = data
+		return res, nil
+	}
+}
+
+// postOnce performs a single request and reads the response body
+func (s *Sender) postOnce(ctx context.Context, webhookURL string, body []byte) (*http.Response, []byte, error) {
+	if s.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.timeout)
+		defer cancel()
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, nil, err
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	respons, err := s.client.Do(request)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer respons.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(respons.Body, maxResponseBodySize))
+	if err != nil {
+		return nil, nil, err
+	}
+	return respons, data, nil
+}
+
+// retryAfter returns the delay of a rate limited response, Slack sends it in seconds
+func retryAfter(resp *http.Response) time.Duration {
+	seconds, err := strconv.Atoi(resp.Header.Get("Retry-After"))
+	if err != nil || seconds <= 0 {
+		return time.Second
+	}
+	if d := time.Duration(seconds) * time.Second; d < maxRateLimitDelay {
+		return d
+	}
+	return maxRateLimitDelay
+}
+
+// transient reports whether a failed delivery may succeed when retried
+func transient(err error) bool {
+	var apiErr *ErrSlackAPI
+	if errors.As(err, &apiErr) {
+		switch apiErr.StatusCode {
+		case http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+		return false
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr) && !errors.Is(err, context.Canceled)
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,103 @@
+package discordrus
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"runtime"
+	"strings"
+
+	"github.com/rotisserie/eris"
+	"github.com/sirupsen/logrus"
+)
+
+// maxInlineStackFrames is the number of stack frames shown in the STACK TRACE embed
+// when the complete trace is attached as stacktrace.txt
+const maxInlineStackFrames = 10
+
+// WithStackTrace renders the stack trace of eris and pkg/errors errors in a
+// "STACK TRACE" embed; long traces are attached in full as stacktrace.txt
+func WithStackTrace() Option {
+	return func(h *Hook) {
+		h.stackTrace = true
+	}
+}
+
+// stackFrame is a single frame of an extracted stack trace
+type stackFrame struct {
+	function string
+	file     string
+	line     int
+}
+
+// errorStack extracts the stack trace of the entry's error, innermost frame first
+func errorStack(entry *logrus.Entry) []stackFrame {
+	err, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok || err == nil {
+		return nil
+	}
+
+	if root := eris.Unpack(err).ErrRoot; len(root.Stack) > 0 {
+		frames := make([]stackFrame, 0, len(root.Stack))
+		for _, f := range root.Stack {
+			frames = append(frames, stackFrame{function: f.Name, file: f.File, line: f.Line})
+		}
+		return frames
+	}
+
+	// pkg/errors: error terdalam yang punya StackTrace() berisi stack paling lengkap
+	var frames []stackFrame
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		if pcs, ok := stackTracePCs(e); ok {
+			frames = framesFromPCs(pcs)
+		}
+	}
+	return frames
+}
+
+// stackTracePCs calls a pkg/errors style StackTrace() method returning program counters,
+// without depending on the package
+func stackTracePCs(err error) ([]uintptr, bool) {
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil, false
+	}
+	out := m.Type().Out(0)
+	if out.Kind() != reflect.Slice || out.Elem().Kind() != reflect.Uintptr {
+		return nil, false
+	}
+
+	trace := m.Call(nil)[0]
+	pcs := make([]uintptr, trace.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(trace.Index(i).Uint())
+	}
+	return pcs, len(pcs) > 0
+}
+
+// framesFromPCs resolves program counters as recorded by pkg/errors
+func framesFromPCs(pcs []uintptr) []stackFrame {
+	frames := make([]stackFrame, 0, len(pcs))
+	for _, pc := range pcs {
+		// pkg/errors menyimpan return address, jadi pc-1 menunjuk ke instruksi call
+		fn := runtime.FuncForPC(pc - 1)
+		if fn == nil {
+			continue
+		}
+		file, line := fn.FileLine(pc - 1)
+		frames = append(frames, stackFrame{function: fn.Name(), file: file, line: line})
+	}
+	return frames
+}
+
+// formatStack renders frames the way Go panics do: the function, then its file and line
+func formatStack(frames []stackFrame) string {
+	var b strings.Builder
+	for i, f := range frames {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "%s\n\t%s:%d", f.function, f.file, f.line)
+	}
+	return b.String()
+}
@@ -0,0 +1,82 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// StaleAction is what happens to entries older than the maximum alert age at send time
+type StaleAction int
+
+const (
+	// StaleDrop discards stale entries
+	StaleDrop StaleAction = iota
+	// StaleSummarize replaces stale entries by a single compact embed without attachments
+	StaleSummarize
+)
+
+// maxAlertAge is the staleness setting of a level
+type maxAlertAge struct {
+	age    time.Duration
+	action StaleAction
+}
+
+// WithMaxAlertAge drops or summarizes entries that are older than age when they are about
+// to be sent, e.g. warnings still queued after a long outage. Without levels it applies
+// to all levels; it can be called again to use different limits per level:
+//
+//	discordrus.WithMaxAlertAge(10*time.Minute, discordrus.StaleDrop, logrus.WarnLevel),
+//	discordrus.WithMaxAlertAge(time.Hour, discordrus.StaleSummarize, logrus.ErrorLevel),
+func WithMaxAlertAge(age time.Duration, action StaleAction, levels ...logrus.Level) Option {
+	return func(h *Hook) {
+		if len(levels) == 0 {
+			levels = AllLevels
+		}
+		if h.maxAlertAges == nil {
+			h.maxAlertAges = make(map[logrus.Level]maxAlertAge, len(levels))
+		}
+		for _, l := range levels {
+			h.maxAlertAges[l] = maxAlertAge{age: age, action: action}
+		}
+	}
+}
+
+// dropStale applies the maximum alert age to the message and reports whether it must be dropped
+// Stale messages that are summarized are rewritten in place.
+func (h *Hook) dropStale(m *message) bool {
+	if m.entry == nil || m.entry.Time.IsZero() {
+		return false
+	}
+	limit, ok := h.maxAlertAges[m.entry.Level]
+	if !ok || limit.age <= 0 || time.Since(m.entry.Time) <= limit.age {
+		return false
+	}
+	if limit.action == StaleDrop {
+		return true
+	}
+
+	// Ringkas menjadi satu embed, tanpa attachment
+	var p WebhookPayload
+	if err := json.Unmarshal(m.Payload, &p); err != nil {
+		return false
+	}
+	p.Embeds = []Embed{{
+		Title:       "STALE " + strings.ToUpper(m.entry.Level.String()),
+		Description: "```" + previewText(m.entry.Message, maxPreviewLength) + " ```",
+		Color:       h.colorFor(m.entry.Level),
+		Footer:      &EmbedFooter{Text: fmt.Sprintf("Older than %s when sent", limit.age)},
+		Fields:      []EmbedField{originallyLogged(m.entry.Time)},
+	}}
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return false
+	}
+	m.close()
+	m.Payload = payload
+	m.Files = nil
+	return false
+}
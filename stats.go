@@ -0,0 +1,130 @@
+package discordrus
+
+import (
+	"sync"
+	"time"
+)
+
+// DestinationState describes the health of a webhook destination
+type DestinationState string
+
+const (
+	StateHealthy     DestinationState = "healthy"      // last delivery succeeded (or none attempted yet)
+	StateFailing     DestinationState = "failing"      // last delivery failed
+	StateRateLimited DestinationState = "rate_limited" // sends are paused until Discord's rate limit resets
+	StateUnhealthy   DestinationState = "unhealthy"    // the last health probe failed, see WithHealthProbe
+)
+
+// DestinationStats holds delivery statistics of a single webhook destination
+type DestinationStats struct {
+	WebhookURL          string           `json:"webhook_url"` // Redacted webhook URL
+	Sent                uint64           `json:"sent"`
+	Failed              uint64           `json:"failed"`
+	AvgLatency          time.Duration    `json:"avg_latency"`
+	LastSuccess         time.Time        `json:"last_success,omitempty"`
+	LastFailure         time.Time        `json:"last_failure,omitempty"`
+	LastError           string           `json:"last_error,omitempty"`
+	ConsecutiveFailures int              `json:"consecutive_failures"`
+	State               DestinationState `json:"state"`
+	LastProbe           time.Time        `json:"last_probe,omitempty"`
+	ProbeError          string           `json:"probe_error,omitempty"`
+}
+
+// destination is the per-webhook delivery state
+type destination struct {
+	mu                  sync.Mutex
+	sent                uint64
+	failed              uint64
+	totalLatency        time.Duration
+	lastSuccess         time.Time
+	lastFailure         time.Time
+	lastError           string
+	consecutiveFailures int
+	lastProbe           time.Time
+	probeError          string
+
+	// guildID is the server of the webhook, looked up once for DeliveryResult.URL
+	guildOnce sync.Once
+	guildID   string
+}
+
+// destinationFor returns the delivery state of the given webhook
+func (h *Hook) destinationFor(webhookURL string) *destination {
+	h.destinationsMu.Lock()
+	defer h.destinationsMu.Unlock()
+
+	if h.destinations == nil {
+		h.destinations = make(map[string]*destination)
+	}
+	d, ok := h.destinations[webhookURL]
+	if !ok {
+		d = &destination{}
+		h.destinations[webhookURL] = d
+	}
+	return d
+}
+
+// record stores the result of a delivery attempt
+func (d *destination) record(err error, latency time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err != nil {
+		d.failed++
+		d.lastFailure = time.Now()
+		d.lastError = err.Error()
+		d.consecutiveFailures++
+		return
+	}
+	d.sent++
+	d.totalLatency += latency
+	d.lastSuccess = time.Now()
+	d.consecutiveFailures = 0
+}
+
+// snapshot returns the statistics of the destination, limited reports whether
+// its sends are currently paused by a rate limit
+func (d *destination) snapshot(webhookURL string, limited bool) DestinationStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	s := DestinationStats{
+		WebhookURL:          redactWebhookURL(webhookURL),
+		Sent:                d.sent,
+		Failed:              d.failed,
+		LastSuccess:         d.lastSuccess,
+		LastFailure:         d.lastFailure,
+		LastError:           d.lastError,
+		ConsecutiveFailures: d.consecutiveFailures,
+		State:               StateHealthy,
+		LastProbe:           d.lastProbe,
+		ProbeError:          d.probeError,
+	}
+	if d.sent > 0 {
+		s.AvgLatency = d.totalLatency / time.Duration(d.sent)
+	}
+	if d.consecutiveFailures > 0 {
+		s.State = StateFailing
+	}
+	if d.probeError != "" {
+		s.State = StateUnhealthy
+	}
+	if limited {
+		s.State = StateRateLimited
+	}
+	return s
+}
+
+// Stats returns delivery statistics per webhook destination, keyed by the redacted webhook URL
+// Only destinations that have been used or probed at least once are included.
+func (h *Hook) Stats() map[string]DestinationStats {
+	h.destinationsMu.Lock()
+	defer h.destinationsMu.Unlock()
+
+	stats := make(map[string]DestinationStats, len(h.destinations))
+	for webhookURL, d := range h.destinations {
+		s := d.snapshot(webhookURL, h.rateLimits.Blocked(webhookURL))
+		stats[s.WebhookURL] = s
+	}
+	return stats
+}
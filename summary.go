@@ -0,0 +1,137 @@
+package discordrus
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	// summaryMaxKeys is the number of object keys listed per level of a JSON summary
+	summaryMaxKeys = 15
+
+	// summarySampleLength is the number of characters kept from sampled values
+	summarySampleLength = 60
+)
+
+// WithJSONSummary renders JSON bodies larger than threshold bytes as a structural
+// summary (top-level keys, array lengths, sampled values) instead of the raw content,
+// keeping large payloads debuggable within Discord's field limits
+func WithJSONSummary(threshold int) Option {
+	return func(h *Hook) {
+		h.jsonSummaryAfter = threshold
+	}
+}
+
+// summarizeBody returns a "Body (summary)" field when JSON summaries are enabled
+// and body is a JSON document above the threshold
+func (h *Hook) summarizeBody(body []byte) (EmbedField, bool) {
+	if h.jsonSummaryAfter <= 0 || len(body) <= h.jsonSummaryAfter {
+		return EmbedField{}, false
+	}
+
+	var v any
+	if err := json.Unmarshal(body, &v); err != nil {
+		return EmbedField{}, false
+	}
+
+	summary := fmt.Sprintf("%s, %s\n%s", describeJSON(v), formatBytes(len(body)), summarizeJSON(v))
+	return EmbedField{
+		Name:  h.labelSet().BodySummary,
+		Value: "```" + previewText(strings.TrimRight(summary, "\n"), maxFieldValueLength) + " ```",
+	}, true
+}
+
+// summarizeJSON describes the top-level structure of a JSON value, one line per key
+func summarizeJSON(v any) string {
+	var b strings.Builder
+	switch val := v.(type) {
+	case map[string]any:
+		keys := sortedKeys(val)
+		for i, k := range keys {
+			if i == summaryMaxKeys {
+				fmt.Fprintf(&b, "… %d more key(s)\n", len(keys)-i)
+				break
+			}
+			fmt.Fprintf(&b, "%s: %s\n", k, describeJSON(val[k]))
+			if arr, ok := val[k].([]any); ok && len(arr) > 0 {
+				fmt.Fprintf(&b, "  [0]: %s\n", sampleJSON(arr[0]))
+			}
+		}
+	case []any:
+		if len(val) > 0 {
+			fmt.Fprintf(&b, "[0]: %s\n", sampleJSON(val[0]))
+		}
+		if len(val) > 1 {
+			fmt.Fprintf(&b, "[%d]: %s\n", len(val)-1, sampleJSON(val[len(val)-1]))
+		}
+	}
+	return b.String()
+}
+
+// describeJSON returns a one-line description of a JSON value
+func describeJSON(v any) string {
+	switch val := v.(type) {
+	case map[string]any:
+		keys := sortedKeys(val)
+		if len(keys) > 5 {
+			keys = append(keys[:5], "…")
+		}
+		return fmt.Sprintf("object{%s}", strings.Join(keys, ", "))
+	case []any:
+		if len(val) == 0 {
+			return "array[0]"
+		}
+		return fmt.Sprintf("array[%d] of %s", len(val), jsonKind(val[0]))
+	default:
+		return fmt.Sprintf("%s = %s", jsonKind(v), sampleJSON(v))
+	}
+}
+
+// sampleJSON returns a shortened JSON rendering of v
+func sampleJSON(v any) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "?"
+	}
+	return previewText(string(b), summarySampleLength)
+}
+
+func jsonKind(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "bool"
+	default:
+		return "null"
+	}
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// formatBytes renders a size as B, KB or MB
+func formatBytes(n int) string {
+	switch {
+	case n >= 1<<20:
+		return fmt.Sprintf("%.2f MB", float64(n)/(1<<20))
+	case n >= 1<<10:
+		return fmt.Sprintf("%.2f KB", float64(n)/(1<<10))
+	default:
+		return fmt.Sprintf("%d B", n)
+	}
+}
@@ -0,0 +1,72 @@
+package discordrus
+
+import (
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// systemFieldKey holds the system snapshot taken when the entry fired, see WithSystemInfo
+const systemFieldKey = "discordrus_system"
+
+// processStart approximates the start of the process for the uptime
+var processStart = time.Now()
+
+// WithSystemInfo adds a "SYSTEM" embed with the Go version, GOOS/GOARCH, the number of
+// goroutines, the heap in use and the uptime of the process at the moment the entry fired
+func WithSystemInfo() Option {
+	return func(h *Hook) {
+		h.systemInfo = true
+	}
+}
+
+// systemSnapshot is the state of the process when an entry fired
+type systemSnapshot struct {
+	Goroutines int
+	HeapInUse  uint64
+	Uptime     time.Duration
+}
+
+// takeSystemSnapshot reads the current state of the process
+func takeSystemSnapshot() systemSnapshot {
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	return systemSnapshot{
+		Goroutines: runtime.NumGoroutine(),
+		HeapInUse:  mem.HeapInuse,
+		Uptime:     time.Since(processStart),
+	}
+}
+
+// addSystemInfo stores a system snapshot in the entry, so asynchronous deliveries show
+// the state at the time of the entry
+func (h *Hook) addSystemInfo(entry *logrus.Entry) {
+	if h.systemInfo {
+		entry.Data[systemFieldKey] = takeSystemSnapshot()
+	}
+}
+
+// systemEmbed renders the system snapshot of the entry, taken now if it has none
+func (h *Hook) systemEmbed(entry *logrus.Entry, color int) (Embed, bool) {
+	if !h.systemInfo {
+		return Embed{}, false
+	}
+	s, ok := entry.Data[systemFieldKey].(systemSnapshot)
+	if !ok {
+		s = takeSystemSnapshot()
+	}
+	labels := h.labelSet()
+	return Embed{
+		Title: labels.System,
+		Fields: []EmbedField{
+			{Name: labels.GoVersion, Value: "```" + runtime.Version() + " ```", Inline: true},
+			{Name: labels.Platform, Value: "```" + runtime.GOOS + "/" + runtime.GOARCH + " ```", Inline: true},
+			{Name: labels.Goroutines, Value: "```" + strconv.Itoa(s.Goroutines) + " ```", Inline: true},
+			{Name: labels.HeapInUse, Value: "```" + formatBytes(int(s.HeapInUse)) + " ```", Inline: true},
+			{Name: labels.Uptime, Value: "```" + s.Uptime.Round(time.Second).String() + " ```", Inline: true},
+		},
+		Color: color,
+	}, true
+}
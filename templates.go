@@ -0,0 +1,166 @@
+package discordrus
+
+import (
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/rotisserie/eris"
+	"github.com/sirupsen/logrus"
+)
+
+// templateFuncs are the functions available in embed templates besides the builtins
+var templateFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// TemplateData is the data embed templates are executed with, see WithTitleTemplate
+type TemplateData struct {
+	Level       string         // Level in upper case, e.g. "ERROR"
+	Message     string         // Message of the entry
+	Error       string         // Error of the entry, empty without one
+	Fields      map[string]any // Entry fields, without the request, response and other reserved fields
+	Time        time.Time
+	Fingerprint string // See Fingerprint
+
+	AppName     string
+	AppVersion  string
+	Environment string
+	Hostname    string
+
+	Request TemplateRequest // Zero when the entry carries no request
+}
+
+// TemplateRequest is the request of an entry in TemplateData
+type TemplateRequest struct {
+	Method string // HTTP method, or the full gRPC method name
+	URL    string // Redacted URL
+	Path   string
+}
+
+// embedTemplates are the templates of the level embed
+type embedTemplates struct {
+	title       *template.Template
+	description *template.Template
+	fields      []fieldTemplate
+}
+
+// fieldTemplate is a field of the level embed rendered from a template
+type fieldTemplate struct {
+	name string
+	tmpl *template.Template
+}
+
+// WithTitleTemplate sets the title of the level embed from a text/template executed with
+// TemplateData, e.g.
+//
+//	discordrus.WithTitleTemplate("[{{.Level}}] {{.AppName}}")
+//
+// The functions upper and lower are available. It panics if the template cannot be
+// parsed; when executing it fails, the default title is kept and the error is reported
+// to OnError.
+func WithTitleTemplate(text string) Option {
+	tmpl := template.Must(template.New("title").Funcs(templateFuncs).Parse(text))
+	return func(h *Hook) {
+		h.templates.title = tmpl
+	}
+}
+
+// WithDescriptionTemplate sets the description of the level embed from a template,
+// see WithTitleTemplate, e.g.
+//
+//	discordrus.WithDescriptionTemplate("{{.Message}}{{with .Error}}: {{.}}{{end}}")
+func WithDescriptionTemplate(text string) Option {
+	tmpl := template.Must(template.New("description").Funcs(templateFuncs).Parse(text))
+	return func(h *Hook) {
+		h.templates.description = tmpl
+	}
+}
+
+// WithFieldTemplate adds a field to the level embed whose value is rendered from a
+// template, see WithTitleTemplate. Fields are added in the order of the options; fields
+// rendering to an empty string are left out:
+//
+//	discordrus.WithFieldTemplate("Customer", "{{with .Fields.customer_id}}{{.}} ({{$.Fields.plan}}){{end}}")
+func WithFieldTemplate(name, text string) Option {
+	tmpl := template.Must(template.New(name).Funcs(templateFuncs).Parse(text))
+	return func(h *Hook) {
+		h.templates.fields = append(h.templates.fields, fieldTemplate{name: name, tmpl: tmpl})
+	}
+}
+
+// empty reports whether no template is configured
+func (t *embedTemplates) empty() bool {
+	return t.title == nil && t.description == nil && len(t.fields) == 0
+}
+
+// applyTemplates renders the configured templates into the level embed
+func (h *Hook) applyTemplates(embed *Embed, entry *logrus.Entry, drp *LoggerHttpRequestPayload, errorMessage string) {
+	if h.templates.empty() {
+		return
+	}
+	data := h.templateData(entry, drp, errorMessage)
+
+	if s, ok := h.executeTemplate(h.templates.title, data, entry); ok {
+		embed.Title = s
+	}
+	if s, ok := h.executeTemplate(h.templates.description, data, entry); ok {
+		embed.Description = s
+	}
+	for _, f := range h.templates.fields {
+		if s, ok := h.executeTemplate(f.tmpl, data, entry); ok && strings.TrimSpace(s) != "" {
+			embed.Fields = append(embed.Fields, EmbedField{Name: f.name, Value: "```" + previewText(s, maxFieldValueLength) + " ```"})
+		}
+	}
+}
+
+// executeTemplate renders tmpl, reporting failures to OnError
+func (h *Hook) executeTemplate(tmpl *template.Template, data TemplateData, entry *logrus.Entry) (string, bool) {
+	if tmpl == nil {
+		return "", false
+	}
+	var b strings.Builder
+	if err := tmpl.Execute(&b, data); err != nil {
+		h.reportError(eris.Wrapf(err, "failed to execute embed template %s", tmpl.Name()), entry)
+		return "", false
+	}
+	return b.String(), true
+}
+
+// templateData collects the data of the entry for embed templates
+func (h *Hook) templateData(entry *logrus.Entry, drp *LoggerHttpRequestPayload, errorMessage string) TemplateData {
+	data := TemplateData{
+		Level:       strings.ToUpper(entry.Level.String()),
+		Message:     entry.Message,
+		Error:       errorMessage,
+		Fields:      make(map[string]any, len(entry.Data)),
+		Time:        entry.Time,
+		Fingerprint: h.fingerprint(entry),
+		AppName:     h.identity.appName,
+		AppVersion:  h.identity.appVersion,
+		Environment: h.identity.environment,
+		Hostname:    h.identity.hostname,
+	}
+	for k, v := range entry.Data {
+		if !reservedFieldKeys[k] {
+			data.Fields[k] = v
+		}
+	}
+
+	switch {
+	case drp != nil && drp.Request != nil:
+		data.Request = TemplateRequest{Method: drp.Request.Method, URL: h.requestURL(drp.Request), Path: drp.Request.URL.Path}
+	case drp != nil:
+		data.Request = TemplateRequest{Method: drp.Method, URL: drp.URL}
+		if u, err := url.Parse(drp.URL); err == nil {
+			data.Request.Path = u.Path
+		}
+	default:
+		if grpcReq := captureGrpcPayload(entry, false); grpcReq != nil {
+			data.Request = TemplateRequest{Method: grpcReq.FullMethod, Path: grpcReq.FullMethod}
+		}
+	}
+	return data
+}
@@ -0,0 +1,78 @@
+package discordrus
+
+import "github.com/sirupsen/logrus"
+
+// WithThreadID posts every message into the existing thread with the given id
+// (the webhook's channel must be its parent, e.g. a forum channel)
+func WithThreadID(threadID string) Option {
+	return func(h *Hook) {
+		h.threadID = threadID
+	}
+}
+
+// WithThreadName creates a new thread named by fn for each message, e.g. one per service
+// or error class. It only works for webhooks of forum and media channels; an empty name
+// posts the message without creating a thread.
+func WithThreadName(fn func(entry *logrus.Entry) string) Option {
+	return func(h *Hook) {
+		h.threadName = fn
+	}
+}
+
+// TAGS_FIELD_KEY is the key used to pass forum tags in logrus fields
+// The value can be a string or []string of tag ids or names mapped with WithTagIDs
+const TAGS_FIELD_KEY = "applied_tags"
+
+// maxAppliedTags is the number of tags Discord allows on a forum post
+const maxAppliedTags = 5
+
+// WithAppliedTags applies the given forum tags to every thread created by the hook,
+// in addition to the tags passed in entry.Data[TAGS_FIELD_KEY]. Discord expects tag ids;
+// names such as "production" can be used once they are mapped with WithTagIDs.
+func WithAppliedTags(tags ...string) Option {
+	return func(h *Hook) {
+		h.appliedTags = append(h.appliedTags, tags...)
+	}
+}
+
+// WithTagIDs maps tag names to the ids of the forum channel's tags, e.g.
+// {"production": "1234567890"}, so tags can be referred to by name
+func WithTagIDs(ids map[string]string) Option {
+	return func(h *Hook) {
+		if h.tagIDs == nil {
+			h.tagIDs = make(map[string]string, len(ids))
+		}
+		for name, id := range ids {
+			h.tagIDs[name] = id
+		}
+	}
+}
+
+// appliedTagsFor returns the forum tag ids for the entry, without duplicates
+func (h *Hook) appliedTagsFor(entry *logrus.Entry) []string {
+	tags := append([]string(nil), h.appliedTags...)
+	switch v := entry.Data[TAGS_FIELD_KEY].(type) {
+	case string:
+		tags = append(tags, v)
+	case []string:
+		tags = append(tags, v...)
+	}
+
+	var ids []string
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		if id, ok := h.tagIDs[tag]; ok {
+			tag = id
+		}
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		ids = append(ids, tag)
+	}
+	// Discord menolak post dengan lebih dari 5 tag
+	if len(ids) > maxAppliedTags {
+		ids = ids[:maxAppliedTags]
+	}
+	return ids
+}
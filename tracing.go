@@ -0,0 +1,91 @@
+package discordrus
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// WithTraceIDField shows the correlation ID of every entry as the first field of the level
+// embed, so the full story can be found in central logs. The ID is taken from the entry
+// field key, e.g. "trace_id" or "request_id", or from the OpenTelemetry span in
+// entry.Context (logger.WithContext(ctx)) when the entry has no such field. Entries with
+// a span show its trace and span ID even without this option.
+func WithTraceIDField(key string) Option {
+	return func(h *Hook) {
+		h.traceIDField = key
+	}
+}
+
+// traceID returns the correlation ID of the entry, see WithTraceIDField
+func (h *Hook) traceID(entry *logrus.Entry) string {
+	if v, ok := entry.Data[h.traceIDField]; ok {
+		return formatFieldValue(v)
+	}
+	if sc, ok := spanContext(entry); ok {
+		return sc.TraceID().String()
+	}
+	return ""
+}
+
+// WithSpanEvents records the error of every processed entry as an exception event on the
+// OpenTelemetry span in entry.Context, with the level, message and fingerprint as
+// attributes, so the trace shows the errors that were reported to Discord. Muted, sampled
+// and deduplicated entries are recorded as well.
+func WithSpanEvents() Option {
+	return func(h *Hook) {
+		h.spanEvents = true
+	}
+}
+
+// spanContext returns the OpenTelemetry span context of the entry, if valid
+func spanContext(entry *logrus.Entry) (trace.SpanContext, bool) {
+	if entry.Context == nil {
+		return trace.SpanContext{}, false
+	}
+	sc := trace.SpanContextFromContext(entry.Context)
+	return sc, sc.IsValid()
+}
+
+// traceFields renders the correlation ID of the entry (see WithTraceIDField) and the
+// OpenTelemetry trace and span ID of the span in entry.Context
+func (h *Hook) traceFields(entry *logrus.Entry) []EmbedField {
+	var fields []EmbedField
+	id := ""
+	if h.traceIDField != "" {
+		id = h.traceID(entry)
+	}
+	sc, hasSpan := spanContext(entry)
+	if id == "" && hasSpan {
+		id = sc.TraceID().String()
+	}
+	if id != "" {
+		fields = append(fields, EmbedField{Name: h.labelSet().TraceID, Value: "```" + previewText(id, maxFieldValueLength) + " ```"})
+	}
+	if hasSpan {
+		fields = append(fields, EmbedField{Name: h.labelSet().SpanID, Value: "```" + sc.SpanID().String() + " ```", Inline: true})
+	}
+	return fields
+}
+
+// recordSpanEvent records the entry's error on the span in its context, see WithSpanEvents
+func (h *Hook) recordSpanEvent(entry *logrus.Entry, fingerprint string) {
+	if !h.spanEvents || entry.Context == nil || nilError(entry) {
+		return
+	}
+	err, ok := entry.Data[logrus.ErrorKey].(error)
+	if !ok || err == nil {
+		return
+	}
+	span := trace.SpanFromContext(entry.Context)
+	if !span.IsRecording() {
+		return
+	}
+	span.RecordError(err, trace.WithAttributes(
+		attribute.String("log.severity", strings.ToUpper(entry.Level.String())),
+		attribute.String("log.message", entry.Message),
+		attribute.String("discordrus.fingerprint", fingerprint),
+	))
+}
@@ -0,0 +1,115 @@
+package discordrus
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// VALIDATION_FIELD_KEY is the key used to pass validation errors in logrus fields
+	// The value can be a map[string]string, map[string][]string, map[string]any or
+	// go-playground/validator's ValidationErrors
+	VALIDATION_FIELD_KEY = "validation_errors"
+
+	// maxEmbedDescriptionLength is the number of characters kept from a rendered embed description
+	// (Discord allows 4096, including the code fence)
+	maxEmbedDescriptionLength = 4000
+)
+
+// fieldError matches go-playground/validator's FieldError without depending on it
+type fieldError interface {
+	Field() string
+	Tag() string
+	Param() string
+	Error() string
+}
+
+// validationRows extracts (field, message) rows from the validation field, or from the
+// error field when it holds validator errors. Rows are sorted by field name.
+func validationRows(entry *logrus.Entry) [][2]string {
+	if v, ok := entry.Data[VALIDATION_FIELD_KEY]; ok {
+		return toValidationRows(v)
+	}
+	if err, ok := entry.Data[logrus.ErrorKey].(error); ok {
+		return fieldErrorRows(err)
+	}
+	return nil
+}
+
+func toValidationRows(v any) [][2]string {
+	var rows [][2]string
+	switch val := v.(type) {
+	case map[string]string:
+		for field, msg := range val {
+			rows = append(rows, [2]string{field, msg})
+		}
+	case map[string][]string:
+		for field, msgs := range val {
+			rows = append(rows, [2]string{field, strings.Join(msgs, "; ")})
+		}
+	case map[string]any:
+		for field, msg := range val {
+			rows = append(rows, [2]string{field, formatFieldValue(msg)})
+		}
+	case error:
+		rows = fieldErrorRows(val)
+		if rows == nil {
+			rows = [][2]string{{"error", val.Error()}}
+		}
+	default:
+		return nil
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i][0] < rows[j][0] })
+	return rows
+}
+
+// fieldErrorRows renders errors that are a slice of validator field errors
+func fieldErrorRows(err error) [][2]string {
+	// Cari slice FieldError di dalam rantai error (mis. validator.ValidationErrors)
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		rv := reflect.ValueOf(e)
+		if rv.Kind() != reflect.Slice || rv.Len() == 0 {
+			continue
+		}
+
+		var rows [][2]string
+		for i := 0; i < rv.Len(); i++ {
+			fe, ok := rv.Index(i).Interface().(fieldError)
+			if !ok {
+				return nil
+			}
+			msg := fe.Tag()
+			if fe.Param() != "" {
+				msg += "=" + fe.Param()
+			}
+			rows = append(rows, [2]string{fe.Field(), msg})
+		}
+		return rows
+	}
+	return nil
+}
+
+// renderTable renders rows as two padded columns
+func renderTable(rows [][2]string) string {
+	width := 0
+	for _, r := range rows {
+		if n := len([]rune(r[0])); n > width {
+			width = n
+		}
+	}
+	if width > 30 {
+		width = 30
+	}
+
+	var b strings.Builder
+	for _, r := range rows {
+		fmt.Fprintf(&b, "%-*s  %s\n", width, r[0], r[1])
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
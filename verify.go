@@ -0,0 +1,103 @@
+package discordrus
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/murbagus/discordrus/sender"
+	"github.com/rotisserie/eris"
+)
+
+// WithDeliveryVerification reads every delivered message back from Discord and reports
+// ErrMessageNotVisible when it is missing or its embeds were dropped. This surfaces
+// content filtering or permission issues where Discord answers 2xx but hides the message.
+// Each delivery costs an additional request.
+func WithDeliveryVerification() Option {
+	return func(h *Hook) {
+		h.verifyDelivery = true
+	}
+}
+
+// sentMessage is the part of a webhook message compared after delivery
+type sentMessage struct {
+	ID        string            `json:"id"`
+	ChannelID string            `json:"channel_id"`
+	Content   string            `json:"content"`
+	Embeds    []json.RawMessage `json:"embeds"`
+
+	Components []json.RawMessage `json:"components"`
+}
+
+// createdMessage returns the message of a wait=true response
+func createdMessage(resp []byte) (sentMessage, error) {
+	var created sentMessage
+	if err := json.Unmarshal(resp, &created); err != nil || created.ID == "" {
+		return created, eris.New("Discord did not return the created message id")
+	}
+	return created, nil
+}
+
+// createdThreadID returns the thread a created message was posted into
+// Messages creating a new thread (thread_name) live in the channel of that thread.
+func (m *message) createdThreadID(created sentMessage) string {
+	if m.threadName != "" {
+		return created.ChannelID
+	}
+	return m.threadID
+}
+
+// sendVerified posts the message and confirms that it is visible
+func (h *Hook) sendVerified(m *message) error {
+	m.wait = true
+	resp, err := h.send(m)
+	if err != nil {
+		return err
+	}
+	created, err := createdMessage(resp)
+	if err != nil {
+		return err
+	}
+
+	var want sentMessage
+	_ = json.Unmarshal(m.Payload, &want)
+	return h.verifyMessage(m.URL, m.createdThreadID(created), created.ID, want)
+}
+
+// verifyMessage fetches the message from the webhook and compares it with what was sent
+func (h *Hook) verifyMessage(webhookURL, threadID, id string, want sentMessage) error {
+	requestURL, err := (&sender.Message{WebhookURL: webhookURL, EditID: id, ThreadID: threadID}).RequestURL()
+	if err != nil {
+		return err
+	}
+	respons, data, err := h.sender().Get(context.Background(), webhookURL, requestURL)
+	if err != nil {
+		return eris.Wrap(err, "failed to verify Discord message")
+	}
+
+	if respons.StatusCode == http.StatusNotFound {
+		return eris.Wrapf(ErrMessageNotVisible, "message %s not found", id)
+	}
+	if respons.StatusCode >= 300 {
+		return sender.APIError(respons)
+	}
+
+	var got sentMessage
+	if err := json.Unmarshal(data, &got); err != nil {
+		return eris.Wrap(err, "failed to decode Discord message")
+	}
+	// Pesan component tidak memiliki embed, cukup dipastikan component-nya tampil
+	if h.componentsV2 && len(got.Embeds) == 0 {
+		if len(got.Components) == 0 {
+			return eris.Wrapf(ErrMessageNotVisible, "message %s has no components", id)
+		}
+		return nil
+	}
+	if len(got.Embeds) < len(want.Embeds) {
+		return eris.Wrapf(ErrMessageNotVisible, "message %s shows %d of %d embeds", id, len(got.Embeds), len(want.Embeds))
+	}
+	if want.Content != "" && got.Content == "" {
+		return eris.Wrapf(ErrMessageNotVisible, "message %s has no content", id)
+	}
+	return nil
+}
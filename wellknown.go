@@ -0,0 +1,83 @@
+package discordrus
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// wellKnownField is a common metadata field rendered as a dedicated, inline embed field
+type wellKnownField struct {
+	name string   // Field name shown in Discord
+	keys []string // Entry field keys, the first one present is used
+}
+
+// wellKnownFields are shown first, in this order, before the remaining entry fields
+var wellKnownFields = []wellKnownField{
+	{name: "Environment", keys: []string{"env", "environment"}},
+	{name: "Version", keys: []string{"version", "app_version"}},
+	{name: "Tenant", keys: []string{"tenant", "tenant_id"}},
+	{name: "User", keys: []string{"user_id", "userId", "uid"}},
+	{name: "Trace", keys: []string{"trace_id", "traceId", "trace"}},
+	{name: "Duration", keys: []string{"duration", "duration_ms", "elapsed"}},
+}
+
+// WithoutWellKnownFields renders common fields such as user_id, tenant, env, version,
+// trace_id and duration like any other entry field, instead of as dedicated fields
+func WithoutWellKnownFields() Option {
+	return func(h *Hook) {
+		h.noWellKnownFields = true
+	}
+}
+
+// wellKnownEntryFields renders the well-known fields present in the entry and returns
+// the keys it used
+func (h *Hook) wellKnownEntryFields(entry *logrus.Entry) ([]EmbedField, map[string]bool) {
+	if h.noWellKnownFields {
+		return nil, nil
+	}
+
+	var fields []EmbedField
+	used := make(map[string]bool)
+	for _, wk := range wellKnownFields {
+		for _, key := range wk.keys {
+			v, ok := entry.Data[key]
+			if !ok || h.excludedFields[key] || reservedFieldKeys[key] || key == h.traceIDField {
+				continue
+			}
+			used[key] = true
+
+			text := formatFieldValue(v)
+			if wk.name == "Duration" {
+				text = formatDurationValue(key, v)
+			}
+			text = h.redaction.redactField(key, text)
+			value := "`" + strings.ReplaceAll(previewText(text, maxFieldValueLength), "`", "'") + "`"
+			if _, custom := h.fieldRenderings[key]; custom {
+				value = h.renderFieldValue(key, text)
+			}
+			fields = append(fields, EmbedField{Name: wk.name, Value: value, Inline: true})
+			break
+		}
+	}
+	return fields, used
+}
+
+// formatDurationValue renders a duration field, numbers in a "_ms" field are milliseconds
+func formatDurationValue(key string, v any) string {
+	switch val := v.(type) {
+	case time.Duration:
+		if val >= time.Second {
+			return val.Round(time.Millisecond).String()
+		}
+		return val.String()
+	case int, int64, float64:
+		if strings.HasSuffix(key, "_ms") {
+			ms, _ := strconv.ParseFloat(formatFieldValue(val), 64)
+			return time.Duration(ms * float64(time.Millisecond)).String()
+		}
+	}
+	return formatFieldValue(v)
+}